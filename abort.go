@@ -0,0 +1,45 @@
+package capture
+
+// OutputAbortOnStderr captures the stdout and stderr output produced
+// during execution of a supplied function up to and including the first
+// line of stderr output, signalling the function to abort once that
+// line has been captured.
+//
+// The function is passed an abort channel, which is closed once a line
+// of stderr output has been captured.  The function should return
+// promptly once the channel is closed; this relies on the function
+// honouring the channel (a function that does not will run to
+// completion, with any further output discarded).  This allows a test
+// of a process that reports errors (to stderr) without stopping to end
+// at the first error.
+//
+// Any output after the first line of stderr output (i.e. produced before
+// the function returns) is discarded.  The streams are captured
+// independently, so stdout output written before the stderr line is
+// discarded if it is captured after the stderr line.  If no stderr
+// output is captured, the channel is not closed and all output is
+// returned.
+//
+// Error handling is identical to OutputStream.
+//
+// Example:
+//
+//	  func TestProcess(t *testing.T) {
+//		stdout, stderr, err := capture.OutputAbortOnStderr(func (abort <-chan struct{}) error {
+//		   for _, item := range items {
+//		      select {
+//		      case <-abort:
+//		         return nil
+//		      default:
+//		         process(item) // reports any error to stderr
+//		      }
+//		   }
+//		   return nil
+//		})
+//		...
+//	  }
+func OutputAbortOnStderr(fn func(abort <-chan struct{}) error) ([]string, []string, error) {
+	return outputUntil(func(st Stream, _ string) bool {
+		return st == StderrStream
+	}, fn)
+}
@@ -0,0 +1,82 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOutputAbortOnStderr(t *testing.T) {
+	t.Run("when stderr output is captured", func(t *testing.T) {
+		// ARRANGE
+		fnerr := errors.New("function error")
+		var processed int
+
+		// ACT
+		stdout, stderr, err := OutputAbortOnStderr(func(abort <-chan struct{}) error {
+			for i := 1; i <= 100; i++ {
+				select {
+				case <-abort:
+					return fnerr
+				case <-time.After(time.Millisecond):
+				}
+				processed = i
+				if i == 3 {
+					fmt.Fprintf(os.Stderr, "item %d failed\n", i)
+					continue
+				}
+				if i < 3 {
+					fmt.Printf("item %d ok\n", i)
+				}
+			}
+			return nil
+		})
+
+		// ASSERT
+		t.Run("returns error", func(t *testing.T) {
+			wanted := fnerr
+			got := err
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+
+		t.Run("aborted", func(t *testing.T) {
+			if processed == 100 {
+				t.Error("function not aborted")
+			}
+		})
+
+		t.Run("output", func(t *testing.T) {
+			wanted := [][]string{{"item 1 ok", "item 2 ok"}, {"item 3 failed"}}
+			got := [][]string{stdout, stderr}
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	})
+
+	t.Run("when no stderr output is captured", func(t *testing.T) {
+		// ACT
+		stdout, stderr, err := OutputAbortOnStderr(func(abort <-chan struct{}) error {
+			fmt.Println("ok")
+			select {
+			case <-abort:
+				return errors.New("aborted")
+			default:
+				return nil
+			}
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if wanted, got := [][]string{{"ok"}, nil}, [][]string{stdout, stderr}; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package capture
+
+// stripANSI removes ANSI escape sequences from a byte slice:
+//
+//   - CSI sequences (ESC '[', parameter bytes, intermediate bytes and a
+//     final byte), which includes SGR (color and style) sequences;
+//   - OSC sequences (ESC ']' ... terminated by BEL or ESC '\');
+//   - other two-byte escape sequences (ESC followed by a byte in the
+//     range 0x40-0x5f).
+//
+// An incomplete sequence at the end of the input is also removed.
+func stripANSI(b []byte) []byte {
+	const (
+		text = iota
+		esc
+		csi
+		osc
+		oscEsc
+	)
+
+	out := make([]byte, 0, len(b))
+	state := text
+	for _, c := range b {
+		switch state {
+		case text:
+			if c == 0x1b {
+				state = esc
+				continue
+			}
+			out = append(out, c)
+
+		case esc:
+			switch {
+			case c == '[':
+				state = csi
+			case c == ']':
+				state = osc
+			case c >= 0x40 && c <= 0x5f:
+				state = text
+			default:
+				// not a recognised sequence; retain the content
+				out = append(out, 0x1b, c)
+				state = text
+			}
+
+		case csi:
+			// parameter (0x30-0x3f) and intermediate (0x20-0x2f) bytes are
+			// consumed; a final byte (0x40-0x7e) ends the sequence
+			if c >= 0x40 && c <= 0x7e {
+				state = text
+			}
+
+		case osc:
+			switch c {
+			case 0x07:
+				state = text
+			case 0x1b:
+				state = oscEsc
+			}
+
+		case oscEsc:
+			if c == '\\' {
+				state = text
+			} else {
+				state = osc
+			}
+		}
+	}
+
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// OutputPlain captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, removing any ANSI
+// escape sequences (e.g. color codes) from the captured output before
+// it is split into lines.
+//
+// Error handling is identical to Output.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, _, _ := capture.OutputPlain(func () error {
+//		   fmt.Println("\x1b[31mred\x1b[0m")
+//		   return nil
+//		})
+//
+//		fmt.Printf("stdout: %v", stdout) // [red]
+//	  }
+func OutputPlain(fn func() error) ([]string, []string, error) {
+	return Output(fn, WithStripANSI())
+}
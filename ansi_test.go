@@ -0,0 +1,68 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestStripANSI(t *testing.T) {
+	testcases := []struct {
+		name   string
+		input  string
+		result string
+	}{
+		{name: "no sequences", input: "plain text", result: "plain text"},
+		{name: "sgr", input: "\x1b[31mred\x1b[0m", result: "red"},
+		{name: "sgr with multiple parameters", input: "\x1b[1;38;5;208mbold orange\x1b[m", result: "bold orange"},
+		{name: "cursor movement", input: "a\x1b[2Kb\x1b[1Ac", result: "abc"},
+		{name: "private parameters", input: "\x1b[?25lhidden cursor\x1b[?25h", result: "hidden cursor"},
+		{name: "osc terminated by BEL", input: "\x1b]0;title\x07text", result: "text"},
+		{name: "osc terminated by ST", input: "\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\", result: "link"},
+		{name: "two-byte sequence", input: "a\x1bMb", result: "ab"},
+		{name: "unrecognised sequence", input: "a\x1b!b", result: "a\x1b!b"},
+		{name: "incomplete sequence", input: "text\x1b[3", result: "text"},
+		{name: "multibyte content", input: "\x1b[32m✓ 完了\x1b[0m", result: "✓ 完了"},
+		{name: "only sequences", input: "\x1b[0m", result: ""},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ACT
+			result := stripANSI([]byte(tc.input))
+
+			// ASSERT
+			wanted := tc.result
+			got := string(result)
+			if wanted != got {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	}
+}
+
+func TestOutputPlain(t *testing.T) {
+	// ACT
+	stdout, stderr, err := OutputPlain(func() error {
+		fmt.Println("\x1b[31mred\x1b[0m")
+		fmt.Println("\x1b[1mbold\x1b[0m text")
+		os.Stderr.WriteString("\x1b[33mwarning\x1b[0m\n")
+		return nil
+	})
+
+	// ASSERT
+	t.Run("no error", func(t *testing.T) {
+		got := err
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+
+	t.Run("output captured without escape sequences", func(t *testing.T) {
+		wanted := [][]string{{"red", "bold text"}, {"warning"}}
+		got := [][]string{stdout, stderr}
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,297 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// AssertStdout captures the stdout output produced during execution of
+// a supplied function (as for Stdout) and compares it with the wanted
+// lines, failing the test (using t.Errorf) if the captured output is
+// different or if an error is returned.
+//
+// Differences are reported line-by-line.
+//
+// Example:
+//
+//	  func TestSomething(t *testing.T) {
+//		capture.AssertStdout(t, []string{"some output"}, func () error {
+//		   fmt.Println("some output")
+//		   return nil
+//		})
+//	  }
+func AssertStdout(t testing.TB, want []string, fn func() error) {
+	t.Helper()
+	got, err := Stdout(fn)
+	assertLines(t, "stdout", want, got, err)
+}
+
+// AssertStderr captures the stderr output produced during execution of
+// a supplied function (as for Stderr) and compares it with the wanted
+// lines, failing the test (using t.Errorf) if the captured output is
+// different or if an error is returned.
+//
+// Differences are reported line-by-line.
+//
+// Example:
+//
+//	  func TestSomething(t *testing.T) {
+//		capture.AssertStderr(t, []string{"some output"}, func () error {
+//		   fmt.Fprintln(os.Stderr, "some output")
+//		   return nil
+//		})
+//	  }
+func AssertStderr(t testing.TB, want []string, fn func() error) {
+	t.Helper()
+	got, err := Stderr(fn)
+	assertLines(t, "stderr", want, got, err)
+}
+
+// OutputSub runs a named subtest (using t.Run) in which the stdout
+// output produced during execution of a supplied function is captured
+// and compared with the wanted lines, as for AssertStdout.  Any error
+// returned by the function, or any difference in the captured output,
+// fails the subtest.
+//
+// This supports table-driven tests in which each row runs a function
+// and checks its output.
+//
+// The subtest runs on a new goroutine, so OutputSub must not be called
+// while a capture is in progress on the calling goroutine (e.g. in a
+// function passed to Output, or while a capture started by Begin or a
+// Capturer is in progress); the capture in the subtest blocks until
+// that capture has stopped, which it cannot, since t.Run does not
+// return until the subtest has completed, and the test deadlocks.
+//
+// Example:
+//
+//	  func TestCommands(t *testing.T) {
+//		testcases := []struct {
+//		   name string
+//		   args []string
+//		   want []string
+//		}{
+//		   {name: "version", args: []string{"--version"}, want: []string{"cmd 1.0"}},
+//		   {name: "help", args: []string{"--help"}, want: []string{"usage: cmd"}},
+//		}
+//		for _, tc := range testcases {
+//		   capture.OutputSub(t, tc.name, tc.want, func () error {
+//		      return run(tc.args)
+//		   })
+//		}
+//	  }
+func OutputSub(t *testing.T, name string, want []string, fn func() error) {
+	t.Helper()
+	t.Run(name, func(t *testing.T) {
+		t.Helper()
+		AssertStdout(t, want, fn)
+	})
+}
+
+// OutputExpect captures the stdout and stderr output produced during
+// execution of a supplied function (as for Output), failing the test
+// (using t.Errorf) if the error returned does not match the wanted
+// error or if the captured stderr output is different from the wanted
+// lines.  Captured stdout output is ignored.
+//
+// The error matches if errors.Is(err, wantErr) is true or, if wantErr
+// is nil, if no error was returned.  All mismatches are reported,
+// with stderr differences reported line-by-line.
+//
+// Example:
+//
+//	  func TestInvalidArgs(t *testing.T) {
+//		capture.OutputExpect(t, ErrInvalidArgs, []string{"usage: cmd <file>"}, func () error {
+//		   return run([]string{})
+//		})
+//	  }
+func OutputExpect(t testing.TB, wantErr error, wantStderr []string, fn func() error) {
+	t.Helper()
+	_, stderr, err := Output(fn)
+
+	switch {
+	case wantErr == nil && err != nil:
+		t.Errorf("error:\n  wanted: <nil>\n  got   : %v", err)
+	case wantErr != nil && !errors.Is(err, wantErr):
+		t.Errorf("error:\n  wanted: %v\n  got   : %v", wantErr, err)
+	}
+	if diff := diffLines(wantStderr, stderr); diff != "" {
+		t.Errorf("stderr:\n%s", diff)
+	}
+}
+
+// OutputSilent captures the stdout and stderr output produced during
+// execution of a supplied function (as for Output), failing the test
+// (using t.Errorf) if any output is captured, listing the lines
+// captured from each stream.  This guards against unwanted output, such
+// as debugging output inadvertently left in the code under test.
+//
+// The error returned by the function (joined with any capture errors)
+// is returned, for any further assertions.
+//
+// Example:
+//
+//	  func TestQuietMode(t *testing.T) {
+//		err := capture.OutputSilent(t, func () error {
+//		   return run([]string{"--quiet"})
+//		})
+//		if err != nil {
+//		   t.Fatal(err)
+//		}
+//	  }
+func OutputSilent(t testing.TB, fn func() error) error {
+	t.Helper()
+	stdout, stderr, err := Output(fn)
+
+	for _, s := range []struct {
+		name  string
+		lines []string
+	}{
+		{"stdout", stdout},
+		{"stderr", stderr},
+	} {
+		if len(s.lines) == 0 {
+			continue
+		}
+		sb := &strings.Builder{}
+		for i, l := range s.lines {
+			fmt.Fprintf(sb, "line %d: %q\n", i+1, l)
+		}
+		t.Errorf("%s: unexpected output:\n%s", s.name, sb)
+	}
+
+	return err
+}
+
+// AssertLinesUnordered compares captured lines with wanted lines,
+// ignoring the order of the lines, failing the test (using t.Errorf) if
+// they are different.  The lines are compared as multisets: each wanted
+// line must be matched by a different captured line, so a line repeated
+// in one but not the other is a difference.
+//
+// This is useful when asserting output that is deterministic in content
+// but not in order, such as output written by concurrent goroutines.
+//
+// Lines that are wanted but were not captured are reported separately
+// from lines that were captured but not wanted.
+//
+// Example:
+//
+//	  func TestWorkers(t *testing.T) {
+//		stdout, _, _ := capture.Output(func () error {
+//		   return runWorkers(2)
+//		})
+//		capture.AssertLinesUnordered(t, []string{"worker 1 done", "worker 2 done"}, stdout)
+//	  }
+func AssertLinesUnordered(t testing.TB, want []string, got []string) {
+	t.Helper()
+	if diff := diffUnordered(want, got); diff != "" {
+		t.Errorf("lines (ignoring order):\n%s", diff)
+	}
+}
+
+// OutputUnorderedEqual captures the stdout and stderr output produced
+// during execution of a supplied function (as for Output) and compares
+// the output captured from each stream with the wanted lines, ignoring
+// the order of the lines (as for AssertLinesUnordered), failing the
+// test (using t.Errorf) if the captured output is different or if an
+// error is returned.
+//
+// Example:
+//
+//	  func TestWorkers(t *testing.T) {
+//		capture.OutputUnorderedEqual(t, []string{"worker 1 done", "worker 2 done"}, nil, func () error {
+//		   return runWorkers(2)
+//		})
+//	  }
+func OutputUnorderedEqual(t testing.TB, wantStdout, wantStderr []string, fn func() error) {
+	t.Helper()
+	stdout, stderr, err := Output(fn)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if diff := diffUnordered(wantStdout, stdout); diff != "" {
+		t.Errorf("stdout (ignoring order):\n%s", diff)
+	}
+	if diff := diffUnordered(wantStderr, stderr); diff != "" {
+		t.Errorf("stderr (ignoring order):\n%s", diff)
+	}
+}
+
+// assertLines fails a test if an error is not nil or if the wanted and
+// captured lines are different.
+func assertLines(t testing.TB, name string, want, got []string, err error) {
+	t.Helper()
+	if err != nil {
+		t.Errorf("%s: unexpected error: %v", name, err)
+	}
+	if diff := diffLines(want, got); diff != "" {
+		t.Errorf("%s:\n%s", name, diff)
+	}
+}
+
+// diffLines returns a line-by-line description of the differences
+// between wanted and captured lines, or "" if there are no differences.
+func diffLines(want, got []string) string {
+	sb := &strings.Builder{}
+	for i := 0; i < len(want) || i < len(got); i++ {
+		switch {
+		case i >= len(got):
+			fmt.Fprintf(sb, "line %d:\n  wanted: %q\n  got   : <missing>\n", i+1, want[i])
+		case i >= len(want):
+			fmt.Fprintf(sb, "line %d:\n  wanted: <none>\n  got   : %q\n", i+1, got[i])
+		case want[i] != got[i]:
+			fmt.Fprintf(sb, "line %d:\n  wanted: %q\n  got   : %q\n", i+1, want[i], got[i])
+		}
+	}
+	return sb.String()
+}
+
+// diffUnordered returns a description of the lines that are wanted but
+// were not captured and the lines that were captured but not wanted,
+// ignoring the order of the lines, or "" if there are no differences.
+// Missing lines are listed in the order wanted and extra lines in the
+// order captured.
+func diffUnordered(want, got []string) string {
+	count := make(map[string]int, len(got))
+	for _, l := range got {
+		count[l]++
+	}
+
+	var missing []string
+	for _, l := range want {
+		if count[l] == 0 {
+			missing = append(missing, l)
+			continue
+		}
+		count[l]--
+	}
+
+	var extra []string
+	for _, l := range got {
+		if count[l] > 0 {
+			extra = append(extra, l)
+			count[l]--
+		}
+	}
+
+	sb := &strings.Builder{}
+	for _, s := range []struct {
+		name  string
+		lines []string
+	}{
+		{"missing", missing},
+		{"extra", extra},
+	} {
+		if len(s.lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(sb, "%s:\n", s.name)
+		for _, l := range s.lines {
+			fmt.Fprintf(sb, "  %q\n", l)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,370 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// mockT is a testing.TB recording any failures and logs reported by a
+// helper, and any cleanup functions registered by it.
+type mockT struct {
+	testing.TB
+	errors   []string
+	logs     []string
+	cleanups []func()
+	failed   bool
+}
+
+func (m *mockT) Helper() {}
+
+func (m *mockT) Errorf(format string, args ...any) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+// Fatalf records the failure and stops the calling goroutine (as for
+// testing.T); a helper expected to call Fatalf must therefore be called
+// on a separate goroutine.
+func (m *mockT) Fatalf(format string, args ...any) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+	runtime.Goexit()
+}
+
+func (m *mockT) Logf(format string, args ...any) {
+	m.logs = append(m.logs, fmt.Sprintf(format, args...))
+}
+
+func (m *mockT) Cleanup(fn func()) { m.cleanups = append(m.cleanups, fn) }
+
+func (m *mockT) Failed() bool { return m.failed || len(m.errors) > 0 }
+
+// cleanup runs any registered cleanup functions, in the reverse order
+// of registration.
+func (m *mockT) cleanup() {
+	for i := len(m.cleanups) - 1; i >= 0; i-- {
+		m.cleanups[i]()
+	}
+}
+
+func TestAssertStdout(t *testing.T) {
+	t.Run("when output matches", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+
+		// ACT
+		AssertStdout(mock, []string{"line 1", "line 2"}, func() error {
+			fmt.Println("line 1")
+			fmt.Println("line 2")
+			return nil
+		})
+
+		// ASSERT
+		if len(mock.errors) != 0 {
+			t.Errorf("\nwanted: no errors\ngot   : %v", mock.errors)
+		}
+	})
+
+	t.Run("when output does not match", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+
+		// ACT
+		AssertStdout(mock, []string{"line 1", "line 2"}, func() error {
+			fmt.Println("line 1")
+			fmt.Println("line two")
+			fmt.Println("line 3")
+			return nil
+		})
+
+		// ASSERT
+		wanted := "stdout:\n" +
+			"line 2:\n  wanted: \"line 2\"\n  got   : \"line two\"\n" +
+			"line 3:\n  wanted: <none>\n  got   : \"line 3\"\n"
+		got := strings.Join(mock.errors, "\n")
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when function returns an error", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+
+		// ACT
+		AssertStdout(mock, nil, func() error { return errors.New("function error") })
+
+		// ASSERT
+		wanted := "stdout: unexpected error: function error"
+		got := strings.Join(mock.errors, "\n")
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
+
+func TestAssertStderr(t *testing.T) {
+	t.Run("when output matches", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+
+		// ACT
+		AssertStderr(mock, []string{"line 1"}, func() error {
+			os.Stderr.WriteString("line 1\n")
+			return nil
+		})
+
+		// ASSERT
+		if len(mock.errors) != 0 {
+			t.Errorf("\nwanted: no errors\ngot   : %v", mock.errors)
+		}
+	})
+
+	t.Run("when output is missing", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+
+		// ACT
+		AssertStderr(mock, []string{"line 1"}, func() error { return nil })
+
+		// ASSERT
+		wanted := "stderr:\nline 1:\n  wanted: \"line 1\"\n  got   : <missing>\n"
+		got := strings.Join(mock.errors, "\n")
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
+
+func TestOutputSub(t *testing.T) {
+	// ARRANGE
+	var names []string
+
+	// ACT
+	for _, tc := range []struct {
+		name string
+		want []string
+	}{
+		{name: "one", want: []string{"one"}},
+		{name: "two", want: []string{"two", "lines"}},
+	} {
+		OutputSub(t, tc.name, tc.want, func() error {
+			names = append(names, tc.name)
+			for _, s := range tc.want {
+				fmt.Println(s)
+			}
+			return nil
+		})
+	}
+
+	// ASSERT
+	wanted := []string{"one", "two"}
+	got := names
+	if !reflect.DeepEqual(wanted, got) {
+		t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+	}
+}
+
+func TestOutputExpect(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	testcases := []struct {
+		name       string
+		wantErr    error
+		wantStderr []string
+		fn         func() error
+		wanted     string
+	}{
+		{name: "when error and stderr match",
+			wantErr:    fnerr,
+			wantStderr: []string{"failed"},
+			fn: func() error {
+				fmt.Println("ignored")
+				os.Stderr.WriteString("failed\n")
+				return fmt.Errorf("wrapped: %w", fnerr)
+			},
+		},
+		{name: "when no error is wanted or returned",
+			fn: func() error { return nil },
+		},
+		{name: "when no error is wanted but one is returned",
+			fn:     func() error { return fnerr },
+			wanted: "error:\n  wanted: <nil>\n  got   : function error",
+		},
+		{name: "when an error is wanted but none is returned",
+			wantErr: fnerr,
+			fn:      func() error { return nil },
+			wanted:  "error:\n  wanted: function error\n  got   : <nil>",
+		},
+		{name: "when error and stderr both mismatch",
+			wantErr:    fnerr,
+			wantStderr: []string{"failed"},
+			fn:         func() error { return errors.New("other error") },
+			wanted: "error:\n  wanted: function error\n  got   : other error\n" +
+				"stderr:\nline 1:\n  wanted: \"failed\"\n  got   : <missing>\n",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ARRANGE
+			mock := &mockT{TB: t}
+
+			// ACT
+			OutputExpect(mock, tc.wantErr, tc.wantStderr, tc.fn)
+
+			// ASSERT
+			wanted := tc.wanted
+			got := strings.Join(mock.errors, "\n")
+			if wanted != got {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	}
+}
+
+func TestOutputSilent(t *testing.T) {
+	t.Run("when silent", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+		fnerr := errors.New("function error")
+
+		// ACT
+		err := OutputSilent(mock, func() error { return fnerr })
+
+		// ASSERT
+		if !errors.Is(err, fnerr) {
+			t.Errorf("error:\nwanted: %v\ngot   : %v", fnerr, err)
+		}
+		if len(mock.errors) != 0 {
+			t.Errorf("\nwanted: no errors\ngot   : %v", mock.errors)
+		}
+	})
+
+	t.Run("when output is produced", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+
+		// ACT
+		err := OutputSilent(mock, func() error {
+			fmt.Println("debug 1")
+			fmt.Println("debug 2")
+			os.Stderr.WriteString("warning\n")
+			return nil
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		wanted := []string{
+			"stdout: unexpected output:\nline 1: \"debug 1\"\nline 2: \"debug 2\"\n",
+			"stderr: unexpected output:\nline 1: \"warning\"\n",
+		}
+		got := mock.errors
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
+
+func TestAssertLinesUnordered(t *testing.T) {
+	testcases := []struct {
+		name   string
+		want   []string
+		got    []string
+		wanted string
+	}{
+		{name: "when lines are equal",
+			want: []string{"a", "b"},
+			got:  []string{"a", "b"},
+		},
+		{name: "when lines are in a different order",
+			want: []string{"a", "b", "a"},
+			got:  []string{"b", "a", "a"},
+		},
+		{name: "when no lines are wanted or captured"},
+		{name: "when lines are missing",
+			want:   []string{"a", "b", "c"},
+			got:    []string{"c"},
+			wanted: "lines (ignoring order):\nmissing:\n  \"a\"\n  \"b\"\n",
+		},
+		{name: "when lines are extra",
+			want:   []string{"a"},
+			got:    []string{"b", "a", "c"},
+			wanted: "lines (ignoring order):\nextra:\n  \"b\"\n  \"c\"\n",
+		},
+		{name: "when lines are missing and extra",
+			want:   []string{"a", "b"},
+			got:    []string{"c", "a"},
+			wanted: "lines (ignoring order):\nmissing:\n  \"b\"\nextra:\n  \"c\"\n",
+		},
+		{name: "when a line is repeated",
+			want:   []string{"a", "a", "b"},
+			got:    []string{"a", "b", "b"},
+			wanted: "lines (ignoring order):\nmissing:\n  \"a\"\nextra:\n  \"b\"\n",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ARRANGE
+			mock := &mockT{TB: t}
+
+			// ACT
+			AssertLinesUnordered(mock, tc.want, tc.got)
+
+			// ASSERT
+			wanted := tc.wanted
+			got := strings.Join(mock.errors, "\n")
+			if wanted != got {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	}
+}
+
+func TestOutputUnorderedEqual(t *testing.T) {
+	t.Run("when output matches", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+
+		// ACT
+		OutputUnorderedEqual(mock, []string{"one", "two"}, []string{"three"}, func() error {
+			fmt.Println("two")
+			fmt.Println("one")
+			os.Stderr.WriteString("three\n")
+			return nil
+		})
+
+		// ASSERT
+		if len(mock.errors) != 0 {
+			t.Errorf("\nwanted: no errors\ngot   : %v", mock.errors)
+		}
+	})
+
+	t.Run("when output does not match", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+		fnerr := errors.New("function error")
+
+		// ACT
+		OutputUnorderedEqual(mock, []string{"one", "two"}, nil, func() error {
+			fmt.Println("two")
+			os.Stderr.WriteString("three\n")
+			return fnerr
+		})
+
+		// ASSERT
+		wanted := "unexpected error: function error\n" +
+			"stdout (ignoring order):\nmissing:\n  \"one\"\n\n" +
+			"stderr (ignoring order):\nextra:\n  \"three\"\n"
+		got := strings.Join(mock.errors, "\n")
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package capture
+
+import (
+	"sync"
+	"testing"
+)
+
+// Begin starts capturing stdout and stderr for the remainder of a test,
+// returning a function that stops the capture and returns the output
+// captured since Begin was called.
+//
+// This avoids wrapping a long test body in a function passed to Output.
+// A cleanup function is registered with the test to restore os.Stdout
+// and os.Stderr when the test (and any cleanup functions registered
+// after Begin was called) completes, so the files are restored even if
+// the test fails (using t.Fatal) or panics without the returned function
+// having been called.  If the returned function is not called before the
+// test completes, any captured output is discarded.
+//
+// If the capture cannot be started (see Capturer.Start), the test is
+// failed and stopped immediately (using t.Fatalf).
+//
+// The returned function may be called more than once; the capture is
+// stopped by the first call and subsequent calls return the same
+// results.  If called after the test has completed, ErrNotStarted is
+// returned.
+//
+// As with all captures, while the capture is in progress any capture
+// started on a different goroutine (e.g. by a parallel test) blocks
+// until the capture has stopped.
+//
+// This includes a subtest of the test, which runs on a new goroutine:
+// while the capture is in progress, a capture started in a subtest run
+// using t.Run (including by OutputSub) blocks until the capture has
+// stopped, and since t.Run does not return until the subtest has
+// completed, the test deadlocks.  Stop the capture before running any
+// subtest that captures output.
+//
+// Example:
+//
+//	  func TestSomething(t *testing.T) {
+//		output := capture.Begin(t)
+//
+//		fmt.Println("some output")
+//		doSomething()
+//
+//		stdout, stderr, err := output()
+//		...
+//	  }
+func Begin(t testing.TB) func() ([]string, []string, error) {
+	t.Helper()
+
+	c := &Capturer{}
+	if err := c.Start(); err != nil {
+		t.Fatalf("capture.Begin: %v", err)
+	}
+	t.Cleanup(c.Reset)
+
+	var (
+		once   sync.Once
+		stdout []string
+		stderr []string
+		err    error
+	)
+	return func() ([]string, []string, error) {
+		once.Do(func() { stdout, stderr, err = c.Stop() })
+		return stdout, stderr, err
+	}
+}
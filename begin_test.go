@@ -0,0 +1,111 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBegin(t *testing.T) {
+	t.Run("captures output", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+		og := os.Stdout
+		defer mock.cleanup()
+
+		// ACT
+		output := Begin(mock)
+		fmt.Println("to stdout")
+		os.Stderr.WriteString("to stderr\n")
+		stdout, stderr, err := output()
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if wanted, got := []string{"to stdout"}, stdout; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("stdout:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		if wanted, got := []string{"to stderr"}, stderr; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("stderr:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		if os.Stdout != og {
+			t.Error("stdout not restored")
+		}
+
+		t.Run("when called again", func(t *testing.T) {
+			again, _, err := output()
+			if err != nil || !reflect.DeepEqual(stdout, again) {
+				t.Errorf("\nwanted: %q, <nil>\ngot   : %q, %v", stdout, again, err)
+			}
+		})
+	})
+
+	t.Run("restored by cleanup", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+		og := os.Stdout
+
+		// ACT
+		output := Begin(mock)
+		fmt.Println("discarded")
+		mock.cleanup()
+
+		// ASSERT
+		if os.Stdout != og {
+			t.Error("stdout not restored")
+		}
+		if _, _, err := output(); !errors.Is(err, ErrNotStarted) {
+			t.Errorf("\nwanted: %v\ngot   : %v", ErrNotStarted, err)
+		}
+	})
+
+	t.Run("restored when test panics", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+		og := os.Stdout
+
+		// ACT
+		func() {
+			defer func() { _ = recover() }()
+			defer mock.cleanup() // as run by the testing package
+			_ = Begin(mock)
+			panic("test panic")
+		}()
+
+		// ASSERT
+		if os.Stdout != og {
+			t.Error("stdout not restored")
+		}
+	})
+
+	t.Run("when the capture cannot be started", func(t *testing.T) {
+		// ARRANGE
+		pipeerr := errors.New("too many open files")
+		og := pipeFn
+		defer func() { pipeFn = og }()
+		pipeFn = func() (*os.File, *os.File, error) { return nil, nil, pipeerr }
+		mock := &mockT{TB: t}
+		returned := false
+
+		// ACT
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = Begin(mock)
+			returned = true
+		}()
+		<-done
+
+		// ASSERT
+		if returned {
+			t.Error("Begin returned")
+		}
+		if len(mock.errors) != 1 || !strings.Contains(mock.errors[0], pipeerr.Error()) {
+			t.Errorf("\nwanted: failure reporting %q\ngot   : %q", pipeerr, mock.errors)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package capture
+
+import "bytes"
+
+// OutputBestEffort captures the stdout and stderr output produced
+// during execution of a supplied function, as for Output, except that
+// output captured before any error capturing that output is returned
+// rather than discarded.
+//
+// If an error occurs while capturing the output of either stream,
+// ErrStdoutCapture and/or ErrStderrCapture are returned, as for Output,
+// together with whatever output was captured from the stream up to the
+// point of the error.  Any output written after the error is lost;
+// such output is typically incomplete, so the returned output should be
+// used only for diagnostic purposes (e.g. to report in a failing test).
+//
+// Of the available options, only WithCopyFunc and WithReadChunkSize
+// apply to OutputBestEffort; any other options are ignored.
+//
+// Example:
+//
+//	  func TestDoSomething(t *testing.T) {
+//		stdout, stderr, err := capture.OutputBestEffort(func () error {
+//		   return doSomething()
+//		})
+//		if errors.Is(err, capture.ErrCapture) {
+//		   t.Logf("partial output: %v %v", stdout, stderr)
+//		}
+//		...
+//	  }
+func OutputBestEffort(fn func() error, opts ...Option) ([]string, []string, error) {
+	cfg := newConfig(opts)
+
+	var (
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+	)
+
+	o := redirect(stdout, stderr, cfg.copier(), 0, fn)
+
+	// unlike Output, the content of the buffers is returned regardless
+	// of any capture error
+	return lines(stdout.String()), lines(stderr.String()), o.join()
+}
@@ -0,0 +1,76 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputBestEffort(t *testing.T) {
+	t.Run("when copying succeeds", func(t *testing.T) {
+		// ACT
+		stdout, stderr, err := OutputBestEffort(func() error {
+			fmt.Println("to stdout")
+			os.Stderr.WriteString("to stderr\n")
+			return nil
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if wanted, got := []string{"to stdout"}, stdout; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("stdout:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		if wanted, got := []string{"to stderr"}, stderr; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("stderr:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when copying fails", func(t *testing.T) {
+		// ARRANGE
+		cpyerr := errors.New("copy error")
+		cp := func(dst io.Writer, src io.Reader) (int64, error) {
+			// copies the first line only, then fails
+			n, _ := io.CopyN(dst, src, int64(len("first line\n")))
+			_, _ = io.Copy(io.Discard, src)
+			return n, cpyerr
+		}
+
+		// ACT
+		stdout, stderr, err := OutputBestEffort(func() error {
+			fmt.Println("first line")
+			fmt.Println("second line")
+			os.Stderr.WriteString("first line\n")
+			return nil
+		}, WithCopyFunc(cp))
+
+		// ASSERT
+		t.Run("returns error", func(t *testing.T) {
+			for _, wanted := range []error{cpyerr, ErrStdoutCapture, ErrStderrCapture} {
+				if got := err; !errors.Is(got, wanted) {
+					t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+				}
+			}
+		})
+
+		t.Run("stdout", func(t *testing.T) {
+			wanted := []string{"first line"}
+			got := stdout
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+
+		t.Run("stderr", func(t *testing.T) {
+			wanted := []string{"first line"}
+			got := stderr
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	})
+}
@@ -0,0 +1,92 @@
+package capture
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// CaptureWriter is an io.Writer that accumulates the output written to
+// it, for code that accepts an io.Writer for its output rather than
+// writing to os.Stdout or os.Stderr.
+//
+// Passing a CaptureWriter to such code captures its output without
+// replacing os.Stdout or os.Stderr, so captures are not serialized
+// and the output of concurrent tests is not affected.  A CaptureWriter
+// is safe for concurrent use.
+//
+// A CaptureWriter is obtained using NewBuffer.
+type CaptureWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// NewBuffer returns a new, empty CaptureWriter.
+//
+// Example:
+//
+//	  func TestReport(t *testing.T) {
+//		w := capture.NewBuffer()
+//		writeReport(w)
+//
+//		fmt.Printf("report: %v", w.Lines())
+//	  }
+func NewBuffer() *CaptureWriter {
+	return &CaptureWriter{}
+}
+
+// Write implements io.Writer.
+func (cw *CaptureWriter) Write(p []byte) (int, error) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	return cw.buf.Write(p)
+}
+
+// Lines returns the lines written to the CaptureWriter, split as for
+// the lines returned by Output.  If nothing has been written, nil is
+// returned.
+func (cw *CaptureWriter) Lines() []string {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	return lines(cw.buf.String())
+}
+
+// Bytes returns a copy of the output written to the CaptureWriter.  If
+// nothing has been written, nil is returned.
+func (cw *CaptureWriter) Bytes() []byte {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	return bytes.Clone(cw.buf.Bytes())
+}
+
+// RunWith calls a supplied function with a writer to which the function
+// should write its output (instead of os.Stdout or os.Stderr), returning
+// any error returned by the function.  The writer is typically a
+// CaptureWriter, from which the output is then obtained.
+//
+// RunWith captures nothing itself; it formalises the pattern of
+// injecting the writer to be used for output.  This requires the
+// cooperation of the code producing the output but, since os.Stdout
+// and os.Stderr are not replaced, the capture is not serialized with
+// any other captures and is unaffected by any output written to
+// os.Stdout or os.Stderr by other goroutines.  Prefer this to Output
+// (and other functions that capture os.Stdout and os.Stderr) for code
+// that accepts an io.Writer for its output, particularly in parallel
+// tests; use Output for code that writes directly to os.Stdout or
+// os.Stderr.
+//
+// Example:
+//
+//	  func TestReport(t *testing.T) {
+//		t.Parallel()
+//
+//		w := capture.NewBuffer()
+//		err := capture.RunWith(w, func(out io.Writer) error {
+//		   return writeReport(out)
+//		})
+//
+//		fmt.Printf("report: %v", w.Lines())
+//	  }
+func RunWith(w io.Writer, fn func(out io.Writer) error) error {
+	return fn(w)
+}
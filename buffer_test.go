@@ -0,0 +1,121 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestCaptureWriter(t *testing.T) {
+	t.Run("when nothing is written", func(t *testing.T) {
+		// ARRANGE
+		w := NewBuffer()
+
+		// ACT
+		result := w.Lines()
+
+		// ASSERT
+		got := result
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+
+	t.Run("lines written", func(t *testing.T) {
+		// ARRANGE
+		w := NewBuffer()
+
+		// ACT
+		fmt.Fprintln(w, "line 1")
+		fmt.Fprint(w, "line 2\r\nline")
+		fmt.Fprintln(w, " 3")
+
+		// ASSERT
+		wanted := []string{"line 1", "line 2", "line 3"}
+		got := w.Lines()
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("concurrent writes", func(t *testing.T) {
+		// ARRANGE
+		w := NewBuffer()
+		wg := &sync.WaitGroup{}
+
+		// ACT
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() { defer wg.Done(); fmt.Fprintln(w, "line") }()
+		}
+		wg.Wait()
+
+		// ASSERT
+		wanted := 10
+		got := len(w.Lines())
+		if wanted != got {
+			t.Errorf("\nwanted: %d lines\ngot   : %d lines", wanted, got)
+		}
+	})
+}
+
+func TestCaptureWriterBytes(t *testing.T) {
+	t.Run("when nothing is written", func(t *testing.T) {
+		if got := NewBuffer().Bytes(); got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %q", got)
+		}
+	})
+
+	t.Run("returns a copy", func(t *testing.T) {
+		// ARRANGE
+		w := NewBuffer()
+		fmt.Fprintln(w, "line 1")
+
+		// ACT
+		b := w.Bytes()
+		b[0] = 'L'
+
+		// ASSERT
+		wanted := "line 1\n"
+		got := string(w.Bytes())
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
+
+func TestRunWith(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	wg := &sync.WaitGroup{}
+	results := make([][]string, 4)
+
+	// ACT
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w := NewBuffer()
+			err := RunWith(w, func(out io.Writer) error {
+				fmt.Fprintf(out, "writer %d\n", i)
+				return fnerr
+			})
+			if !errors.Is(err, fnerr) {
+				t.Errorf("writer %d: error:\nwanted: %v\ngot   : %v", i, fnerr, err)
+			}
+			results[i] = w.Lines()
+		}(i)
+	}
+	wg.Wait()
+
+	// ASSERT
+	for i, got := range results {
+		wanted := []string{fmt.Sprintf("writer %d", i)}
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("writer %d:\nwanted: %q\ngot   : %q", i, wanted, got)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package capture
+
+// OutputBytes captures the stdout and stderr output produced during
+// execution of a supplied function, returning the captured output
+// verbatim.
+//
+// Unlike Output, the captured output is not split into lines; any
+// trailing newlines, carriage returns or non-text content written
+// to os.Stdout or os.Stderr are preserved exactly as written.  If no
+// output was written to a stream, the corresponding result is nil.
+//
+// Error handling is identical to Output; if ErrStdoutCapture or
+// ErrStderrCapture is returned, the corresponding captured output is
+// discarded.
+//
+// Options are applied as for Output.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, stderr, err := capture.OutputBytes(func () error {
+//		   _, err := os.Stdout.Write([]byte{0x00, 0x01, 0x02})
+//		   return err
+//		})
+//
+//		fmt.Printf("stdout: %v", stdout) // [0 1 2]
+//		fmt.Printf("stderr: %v", stderr)
+//		fmt.Printf("error: %v", err)
+//	  }
+func OutputBytes(fn func() error, opts ...Option) ([]byte, []byte, error) {
+	return output(fn, opts...)
+}
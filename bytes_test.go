@@ -0,0 +1,105 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestOutputBytes(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	stdout, stderr, err := OutputBytes(func() error {
+		fmt.Print("line 1\r\nline 2\n\n")
+		_, _ = os.Stderr.Write([]byte{0x00, 0x01, 0x02})
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout captured verbatim", func(t *testing.T) {
+		wanted := []byte("line 1\r\nline 2\n\n")
+		got := stdout
+		if !bytes.Equal(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("stderr captured verbatim", func(t *testing.T) {
+		wanted := []byte{0x00, 0x01, 0x02}
+		got := stderr
+		if !bytes.Equal(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("when no output is produced", func(t *testing.T) {
+		// ACT
+		stdout, stderr, _ := OutputBytes(func() error { return nil })
+
+		// ASSERT
+		t.Run("stdout is nil", func(t *testing.T) {
+			got := stdout
+			if got != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", got)
+			}
+		})
+
+		t.Run("stderr is nil", func(t *testing.T) {
+			got := stderr
+			if got != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", got)
+			}
+		})
+	})
+
+	t.Run("when error copying captured buffers", func(t *testing.T) {
+		// ARRANGE
+		cpyerr := fmt.Errorf("copy error")
+		cp := func(dst io.Writer, src io.Reader) (int64, error) { _, _ = io.Copy(dst, src); return 0, cpyerr }
+
+		// ACT
+		stdout, stderr, err := OutputBytes(func() error { fmt.Println("some output"); return nil }, WithCopyFunc(cp))
+
+		// ASSERT
+		t.Run("errors", func(t *testing.T) {
+			got := err
+
+			wanted := ErrStdoutCapture
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+
+			wanted = ErrStderrCapture
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("stdout is nil", func(t *testing.T) {
+			got := stdout
+			if got != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", got)
+			}
+		})
+
+		t.Run("stderr is nil", func(t *testing.T) {
+			got := stderr
+			if got != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", got)
+			}
+		})
+	})
+}
@@ -7,42 +7,233 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
+	"time"
 )
 
-var copyFn = io.Copy
+// copyFunc is the type of a function that copies captured output from
+// a pipe to the writer in which it is captured.
+type copyFunc func(dst io.Writer, src io.Reader) (int64, error)
+
+var pipeFn = os.Pipe
+
+// lines splits captured output into lines.  A trailing empty line
+// (resulting from output terminated by a newline) is dropped.  If
+// there is no output, nil is returned.
+//
+// Lines may be terminated by "\n" or "\r\n"; any "\r" preceding a "\n"
+// is removed.
+//
+// Output is split only at "\n" bytes, which cannot occur within a
+// multi-byte UTF-8 encoded character, so the content of each line is
+// preserved exactly (other than any "\r" removed, as described); a
+// line consisting only of whitespace is retained, as is a final line
+// consisting only of "\r" (which does not precede a "\n").
+func lines(s string) []string {
+	l := rawLines(s)
+	if n := len(l); n > 0 && l[n-1] == "" {
+		l = l[:n-1]
+	}
+	return l
+}
+
+// rawLines splits captured output into lines, as for lines, except
+// that a trailing empty line is not dropped: output terminated by a
+// newline results in a final "" element.  If there is no output, nil
+// is returned.
+func rawLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	l := strings.Split(s, "\n")
+
+	// the final element is not terminated by a "\n", so any "\r" that it
+	// ends with is not removed
+	for i := range l[:len(l)-1] {
+		l[i] = strings.TrimSuffix(l[i], "\r")
+	}
+	return l
+}
 
 // capture is used to setup the capture of stdout or stderr.
 // The function returns a function that must be called to restore
-// the original stdout or stderr, a function that must be called
-// to close the pipe (completing the capture) and a channel that
-// will receive the captured output.
+// the original stdout or stderr and a function that must be called
+// to close the pipe, completing the capture.
+//
+// If the pipe cannot be created, the file is not redirected and the
+// error (wrapped with ErrPipeCreate) is returned; the same error is
+// returned by the close function, so a caller need only check the
+// returned error if it must know of the failure before closing.  The
+// restore function must still be called.
+//
+// Captures are serialized; capture blocks while any other goroutine
+// has a capture in progress, until that capture has been restored.
+// Captures may be nested; a capture on a goroutine that already holds
+// a capture (or that was started by a capture using mu.spawn) proceeds
+// immediately.
+//
+// Output written to the captured file is copied to the supplied
+// writer, using the supplied copy function, as it is read from the
+// pipe.  The close function returns once all output has been copied,
+// returning any error that occurred while copying.  If the restore
+// function is called without the pipe having been closed (e.g. if a
+// captured function panicked) the pipe is closed, and the copy
+// completed, before the capture lock is released.
+//
+// If drain is > 0, the close function waits at most drain for the copy
+// to complete; if it does not, the copy is abandoned (with any further
+// output discarded) and ErrDrainTimeout is returned.
+//
+// Closing the capture first restores the captured file and only then
+// closes the pipe and waits for the captured output to be copied.
+// Output written to the file (e.g. by a background goroutine) while the
+// capture is being closed is therefore either captured or written to
+// the restored file; a write fails (with os.ErrClosed) only if it was
+// made using the pipe, obtained from the file before it was restored,
+// after the pipe was closed.
+//
+// Any additional files (also) are redirected to the same pipe, so that
+// output written to any of them is copied to the writer; they are
+// restored (in the reverse order) with the captured file, before the
+// pipe is closed.
 //
 // Example:
 //
 //	  func DoSomething() {
-//		rs, cl := capture(&os.Stdout)
+//		buf := &bytes.Buffer{}
+//		rs, cl, _ := capture(&os.Stdout, buf, io.Copy, 0)
 //		defer rs()
 //
 //		fmt.Println("some output")
-//		s, err := cl()
+//		err := cl()
 //
-//		fmt.Println(s) // "some output"
+//		fmt.Println(buf.String()) // "some output"
 //	  }
-func capture(t **os.File) (func(), func() (string, error)) {
-	og := *t
-	r, w, _ := os.Pipe()
-	*t = w
+func capture(t **os.File, dst io.Writer, cp copyFunc, drain time.Duration, also ...**os.File) (func(), func() error, error) {
+	mu.lock()
 
-	c := make(chan string)
-	e := make(chan error)
+	r, w, err := pipeFn()
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrPipeCreate, err)
+		return mu.unlock, func() error { return err }, err
+	}
+	rds := []*redirection{install(t, w)}
+	for _, a := range also {
+		rds = append(rds, install(a, w))
+	}
+
+	var gate *gateWriter
+	if drain > 0 {
+		gate = &gateWriter{w: dst}
+		dst = gate
+	}
+
+	// the channel is buffered so that a copy abandoned after a drain
+	// timeout does not block when it eventually completes
+	e := make(chan error, 1)
 	go func() {
-		var buf bytes.Buffer
-		_, err := copyFn(&buf, r)
-		c <- buf.String()
+		defer r.Close()
+		_, err := cp(dst, r)
 		e <- err
 	}()
 
-	return func() { *t = og }, func() (string, error) { w.Close(); return <-c, <-e }
+	var once sync.Once
+	close := func() error {
+		once.Do(func() {
+			// an empty write synchronizes with any completed write to the
+			// pipe (the file methods are serialized), ordering those writes
+			// (and the reads of the file made to obtain the pipe) before the
+			// file is restored
+			_, _ = w.Write(nil)
+			for i := len(rds) - 1; i >= 0; i-- {
+				rds[i].restore()
+			}
+			w.Close()
+			err = wait(e, drain, func() {
+				gate.close()
+				_ = r.Close() // unblocks the copy, if reading
+			})
+		})
+		return err
+	}
+	restore := func() {
+		_ = close() // ensures the pipe is closed and drained if the capture panicked
+		mu.unlock()
+	}
+
+	return restore, close, nil
+}
+
+// wait returns the error received from a channel.  If drain is > 0 and
+// no error is received within that time, the supplied abandon function
+// is called and ErrDrainTimeout returned.
+func wait(e <-chan error, drain time.Duration, abandon func()) error {
+	if drain <= 0 {
+		return <-e
+	}
+
+	timer := time.NewTimer(drain)
+	defer timer.Stop()
+
+	select {
+	case err := <-e:
+		return err
+	case <-timer.C:
+		abandon()
+		return ErrDrainTimeout
+	}
+}
+
+// gateWriter is an io.Writer that writes to an underlying writer until
+// closed, after which any writes are discarded (and reported as
+// written).  Once close has returned, no further writes are made to
+// the underlying writer.
+type gateWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closed bool
+}
+
+// Write implements io.Writer.
+func (g *gateWriter) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return len(p), nil
+	}
+	return g.w.Write(p)
+}
+
+// close closes the gate.
+func (g *gateWriter) close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.closed = true
+}
+
+// captured returns the content of a buffer holding captured output.
+// If the buffer is empty, or if the capture failed (err is not nil),
+// nil is returned, unless the capture failed only because the output
+// was not drained within a drain timeout, in which case the output
+// captured until the timeout is returned.
+func captured(buf *bytes.Buffer, err error) []byte {
+	if (err != nil && !errors.Is(err, ErrDrainTimeout)) || buf.Len() == 0 {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// outcome holds the errors resulting from capturing the output of a
+// function.
+type outcome struct {
+	err    error // the error returned by the function
+	stdout error // any error capturing stdout, wrapped with ErrStdoutCapture
+	stderr error // any error capturing stderr, wrapped with ErrStderrCapture
+}
+
+// join returns all errors in the outcome, joined.
+func (o outcome) join() error {
+	return errors.Join(o.err, o.stdout, o.stderr)
 }
 
 // Output captures the stdout and stderr output produced during
@@ -62,7 +253,28 @@ func capture(t **os.File) (func(), func() (string, error)) {
 //     both captured outputs are discarded.
 //
 // These errors are returned wrapped with any error returned from
-// the supplied function itself.
+// the supplied function itself.  If a pipe cannot be created for a
+// stream (e.g. if the process has exhausted its file descriptors), the
+// stream is not redirected and the capture error also wraps
+// ErrPipeCreate.
+//
+// Capturing output necessarily involves replacing the process-global
+// os.Stdout and os.Stderr.  Captures are therefore serialized: if
+// Output (or any other capture function) is called while a capture is
+// in progress on another goroutine, the call blocks until the existing
+// capture has completed.
+//
+// Captures may be nested; if the supplied function itself captures
+// output (e.g. by calling Output), the nested capture receives only the
+// output produced during the nested call, with the enclosing capture
+// receiving any other output.
+//
+// Only output written to os.Stdout and os.Stderr as they are during the
+// capture is captured; output written to a file obtained from them
+// before the capture was started is not (see SnapshotStdout).
+//
+// The behaviour of Output may be modified by supplying options; with
+// no options, output is captured as described above.
 //
 // Example:
 //
@@ -75,38 +287,153 @@ func capture(t **os.File) (func(), func() (string, error)) {
 //		fmt.Printf("stderr: %v", stderr)
 //		fmt.Printf("error: %v", err)
 //	  }
-func Output(fn func() error) ([]string, []string, error) {
-	strings := func(s string) []string {
-		if l := strings.Split(s, "\n"); len(l) > 1 || (len(l) == 1 && l[0] != "") {
-			if l[len(l)-1:][0] == "" {
-				l = l[:len(l)-1]
-			}
-			return l
-		}
+func Output(fn func() error, opts ...Option) ([]string, []string, error) {
+	r := OutputResult(fn, opts...)
+	return r.Stdout, r.Stderr, r.Err
+}
+
+// outputSeparate captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, returning the error
+// returned by the function separately from any capture error.
+func outputSeparate(fn func() error) (stdout, stderr []string, fnerr, err error) {
+	// the function error is not returned to Output, so any error
+	// returned by Output is a capture error
+	stdout, stderr, err = Output(func() error {
+		fnerr = fn()
 		return nil
+	})
+	return stdout, stderr, fnerr, err
+}
+
+// output captures the stdout and stderr output produced during
+// execution of a supplied function, returning the captured output
+// verbatim (subject to any options).  Error handling is as described
+// for Output.
+func output(fn func() error, opts ...Option) ([]byte, []byte, error) {
+	return outputTo(&bytes.Buffer{}, &bytes.Buffer{}, fn, opts...)
+}
+
+// outputTo captures output as for output, using the supplied buffers.
+// The returned output may reference the content of the buffers.
+func outputTo(stdout, stderr *bytes.Buffer, fn func() error, opts ...Option) ([]byte, []byte, error) {
+	cfg := newConfig(opts)
+
+	// the capture lock is acquired for the duration, so that any hooks
+	// run while the capture is serialized with any other, and before any
+	// locker, so that the locker is not held while waiting for any other
+	// capture to complete
+	mu.lock()
+	defer mu.unlock()
+
+	if cfg.locker != nil {
+		cfg.locker.Lock()
+		defer cfg.locker.Unlock()
 	}
 
-	restoreStdout, closeout := capture(&os.Stdout)
-	defer restoreStdout()
+	var (
+		outw = cfg.writer(StdoutStream, stdout, cfg.stdoutTee)
+		errw = cfg.writer(StderrStream, stderr, cfg.stderrTee)
+	)
 
-	restoreStderr, closeerr := capture(&os.Stderr)
-	defer restoreStderr()
+	// the redirections are restored (and the pipes drained) before any
+	// deferred discard, which ensures that no temporary file is left if
+	// the function panics
+	defer outw.discard()
+	defer errw.discard()
+
+	if len(cfg.flushers) > 0 {
+		fn = flush(cfg.flushers, fn)
+	}
+	if cfg.stdin != nil {
+		fn = stdin(cfg.stdin, cfg.cancel, fn)
+	}
+
+	// the sink writers are passed (rather than the sinks) so that, in the
+	// absence of any wrapping writers, the buffers are copied into using
+	// bytes.Buffer.ReadFrom, avoiding the allocation of a copy buffer
+	o := redirect(outw.Writer, errw.Writer, cfg.copier(), cfg.drain, fn)
+
+	if err := outw.complete(); err != nil && o.stdout == nil {
+		o.stdout = fmt.Errorf("%w: %w", ErrStdoutCapture, err)
+	}
+	if err := errw.complete(); err != nil && o.stderr == nil {
+		o.stderr = fmt.Errorf("%w: %w", ErrStderrCapture, err)
+	}
+
+	errs := []error{o.join()}
+	if outw.truncated() {
+		errs = append(errs, fmt.Errorf("stdout: %w", ErrTruncated))
+	}
+	if errw.truncated() {
+		errs = append(errs, fmt.Errorf("stderr: %w", ErrTruncated))
+	}
 
 	var (
-		stdout string
-		stderr string
-		err    error
+		so  = cfg.result(captured(stdout, o.stdout))
+		se  = cfg.result(captured(stderr, o.stderr))
+		err = errors.Join(errs...)
 	)
-	errs := []error{fn()}
 
-	if stdout, err = closeout(); err != nil {
-		errs = append(errs, fmt.Errorf("%w: %w", ErrStdoutCapture, err))
-		stdout = "" // discard captured output
+	return so, se, err
+}
+
+// redirect redirects os.Stdout and os.Stderr to the supplied writers
+// during execution of a supplied function, restoring them when the
+// function returns.  Output is copied to the writers using the supplied
+// copy function, waiting at most drain (if > 0) for the copy of each
+// stream to complete once the function has returned.
+func redirect(stdout, stderr io.Writer, cp copyFunc, drain time.Duration, fn func() error) outcome {
+	mu.lock()
+	defer mu.unlock()
+
+	h := startHook()
+
+	restoreStdout, closeout, _ := capture(&os.Stdout, h.tee(StdoutStream, stdout), cp, drain)
+	defer restoreStdout()
+
+	restoreStderr, closeerr, _ := capture(&os.Stderr, h.tee(StderrStream, stderr), cp, drain)
+	defer restoreStderr()
+
+	o := outcome{err: fn()}
+
+	if err := closeout(); err != nil {
+		o.stdout = fmt.Errorf("%w: %w", ErrStdoutCapture, err)
+	}
+	if err := closeerr(); err != nil {
+		o.stderr = fmt.Errorf("%w: %w", ErrStderrCapture, err)
 	}
-	if stderr, err = closeerr(); err != nil {
-		errs = append(errs, fmt.Errorf("%w: %w", ErrStderrCapture, err))
-		stderr = "" // discard captured output
+	h.stop(o.join())
+
+	return o
+}
+
+// captureFile captures the output written to a single file during
+// execution of a supplied function.  If an error occurs while capturing
+// the output, the captured output is discarded and the error is returned
+// wrapped with the supplied sentinel error, joined with any error returned
+// from the function itself.
+func captureFile(t **os.File, sentinel error, cp copyFunc, fn func() error) ([]byte, error) {
+	mu.lock()
+	defer mu.unlock()
+
+	st := StdoutStream
+	if t == &os.Stderr {
+		st = StderrStream
+	}
+	h := startHook()
+
+	buf := &bytes.Buffer{}
+
+	restore, close, _ := capture(t, h.tee(st, buf), cp, 0)
+	defer restore()
+
+	err := fn()
+
+	cerr := close()
+	if cerr != nil {
+		cerr = fmt.Errorf("%w: %w", sentinel, cerr)
 	}
+	h.stop(errors.Join(err, cerr))
 
-	return strings(stdout), strings(stderr), errors.Join(errs...)
+	return captured(buf, cerr), errors.Join(err, cerr)
 }
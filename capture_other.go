@@ -0,0 +1,13 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package capture
+
+import (
+	"errors"
+	"io"
+)
+
+// captureFD is not supported on this platform.
+func captureFD(int, io.Writer, copyFunc) (func() error, error) {
+	return nil, errors.ErrUnsupported
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -73,12 +74,10 @@ func TestCapture(t *testing.T) {
 	t.Run("when error copying captured buffers", func(t *testing.T) {
 		// ARRANGE
 		cpyerr := fmt.Errorf("copy error")
-		og := copyFn
-		defer func() { copyFn = og }()
-		copyFn = func(dst io.Writer, src io.Reader) (int64, error) { _, _ = io.Copy(dst, src); return 0, cpyerr }
+		cpy := func(dst io.Writer, src io.Reader) (int64, error) { _, _ = io.Copy(dst, src); return 0, cpyerr }
 
 		// ACT
-		stdout, stderr, err := Output(func() error { fmt.Println("some output"); return nil })
+		stdout, stderr, err := Output(func() error { fmt.Println("some output"); return nil }, WithCopyFunc(cpy))
 
 		// ASSERT
 		t.Run("errors", func(t *testing.T) {
@@ -110,3 +109,170 @@ func TestCapture(t *testing.T) {
 		})
 	})
 }
+
+func TestLines(t *testing.T) {
+	testcases := []struct {
+		name   string
+		input  string
+		result []string
+	}{
+		{name: "empty", input: "", result: nil},
+		{name: "single line", input: "line", result: []string{"line"}},
+		{name: "single line with newline", input: "line\n", result: []string{"line"}},
+		{name: "multiple lines", input: "line 1\nline 2\n", result: []string{"line 1", "line 2"}},
+		{name: "trailing blank line", input: "line\n\n", result: []string{"line", ""}},
+		{name: "crlf", input: "line 1\r\nline 2\r\n", result: []string{"line 1", "line 2"}},
+		{name: "crlf without trailing newline", input: "line 1\r\nline 2", result: []string{"line 1", "line 2"}},
+		{name: "crlf trailing blank line", input: "line\r\n\r\n", result: []string{"line", ""}},
+		{name: "cr within line", input: "a\rb\n", result: []string{"a\rb"}},
+		{name: "trailing cr", input: "50%\r100%\r", result: []string{"50%\r100%\r"}},
+		{name: "cr after final newline", input: "line\n\r", result: []string{"line", "\r"}},
+		{name: "newline only", input: "\n", result: []string{""}},
+		{name: "newlines only", input: "\n\n\n", result: []string{"", "", ""}},
+		{name: "crlf only", input: "\r\n", result: []string{""}},
+		{name: "whitespace lines", input: " \n\t\n \t ", result: []string{" ", "\t", " \t "}},
+		{name: "emoji", input: "😀 grin\n👍🏽\n🇬🇧\n", result: []string{"😀 grin", "👍🏽", "🇬🇧"}},
+		{name: "cjk", input: "你好\r\n世界\nこんにちは", result: []string{"你好", "世界", "こんにちは"}},
+		{name: "combining characters", input: "e\u0301\n", result: []string{"e\u0301"}},
+		{name: "invalid utf-8", input: "\xff\xfe\n\xe2\x82\n", result: []string{"\xff\xfe", "\xe2\x82"}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ACT
+			result := lines(tc.input)
+
+			// ASSERT
+			wanted := tc.result
+			got := result
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	}
+
+	t.Run("crlf output", func(t *testing.T) {
+		// ACT
+		stdout, _, _ := Output(func() error {
+			fmt.Print("line 1\r\nline 2\r\n")
+			return nil
+		})
+
+		// ASSERT
+		wanted := []string{"line 1", "line 2"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
+
+func TestRawLines(t *testing.T) {
+	testcases := []struct {
+		name   string
+		input  string
+		result []string
+	}{
+		{name: "empty", input: "", result: nil},
+		{name: "newline only", input: "\n", result: []string{"", ""}},
+		{name: "trailing newline", input: "😀\r\n你好\n", result: []string{"😀", "你好", ""}},
+		{name: "trailing cr", input: "line\r", result: []string{"line\r"}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ACT
+			result := rawLines(tc.input)
+
+			// ASSERT
+			wanted := tc.result
+			got := result
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	}
+}
+
+func TestPipeCreateError(t *testing.T) {
+	// ARRANGE
+	pipeerr := errors.New("too many open files")
+	og := pipeFn
+	defer func() { pipeFn = og }()
+
+	// failPipe returns a pipe constructor that fails on the nth call
+	failPipe := func(n int) func() (*os.File, *os.File, error) {
+		calls := 0
+		return func() (*os.File, *os.File, error) {
+			if calls++; calls == n {
+				return nil, nil, pipeerr
+			}
+			return og()
+		}
+	}
+
+	t.Run("when the stderr pipe cannot be created", func(t *testing.T) {
+		// ARRANGE
+		pipeFn = failPipe(2)
+		ogerr := os.Stderr
+		var redirected bool
+
+		// ACT
+		stdout, stderr, err := Output(func() error {
+			redirected = os.Stderr != ogerr
+			fmt.Println("to stdout")
+			return nil
+		})
+
+		// ASSERT
+		for _, wanted := range []error{ErrStderrCapture, ErrPipeCreate, pipeerr} {
+			if !errors.Is(err, wanted) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, err)
+			}
+		}
+		if errors.Is(err, ErrStdoutCapture) {
+			t.Errorf("unexpected error: %v", ErrStdoutCapture)
+		}
+		if redirected {
+			t.Error("os.Stderr was redirected")
+		}
+		if wanted := []string{"to stdout"}; !reflect.DeepEqual(wanted, stdout) {
+			t.Errorf("stdout:\nwanted: %v\ngot   : %v", wanted, stdout)
+		}
+		if stderr != nil {
+			t.Errorf("stderr:\nwanted: nil\ngot   : %v", stderr)
+		}
+	})
+
+	t.Run("when the Combined pipe cannot be created", func(t *testing.T) {
+		// ARRANGE
+		pipeFn = failPipe(1)
+		ogout, ogerr := os.Stdout, os.Stderr
+		var redirected bool
+
+		// ACT
+		_, err := Combined(func() error {
+			redirected = os.Stdout != ogout || os.Stderr != ogerr
+			return nil
+		})
+
+		// ASSERT
+		if !errors.Is(err, ErrPipeCreate) {
+			t.Errorf("\nwanted: %v\ngot   : %v", ErrPipeCreate, err)
+		}
+		if redirected {
+			t.Error("os.Stdout and/or os.Stderr was redirected")
+		}
+	})
+
+	t.Run("capture lock is released", func(t *testing.T) {
+		// ARRANGE
+		pipeFn = og
+
+		// ACT
+		stdout, _, err := Output(func() error { fmt.Println("output"); return nil })
+
+		// ASSERT
+		if err != nil || !reflect.DeepEqual([]string{"output"}, stdout) {
+			t.Errorf("\nwanted: [output], <nil>\ngot   : %v, %v", stdout, err)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package capture
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"syscall"
+)
+
+// captureFD redirects a file descriptor to a pipe, copying any output
+// written to the descriptor to the supplied writer using the supplied
+// copy function.
+//
+// The returned function restores the original file descriptor and
+// waits for all captured output to be copied, returning any error
+// that occurred while copying.  The function may be called more than
+// once; only the first call has any effect.
+func captureFD(fd int, dst io.Writer, cp copyFunc) (func() error, error) {
+	saved, err := syscall.Dup(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	r, w, err := pipeFn()
+	if err != nil {
+		_ = syscall.Close(saved)
+		return nil, fmt.Errorf("%w: %w", ErrPipeCreate, err)
+	}
+
+	if err := dup2(int(w.Fd()), fd); err != nil {
+		_ = syscall.Close(saved)
+		_ = r.Close()
+		_ = w.Close()
+		return nil, err
+	}
+	// the descriptor now refers to the pipe; the original write end is
+	// no longer needed
+	_ = w.Close()
+
+	e := make(chan error)
+	go func() {
+		defer r.Close()
+		_, err := cp(dst, r)
+		e <- err
+	}()
+
+	var (
+		once sync.Once
+		cerr error
+	)
+	release := func() error {
+		once.Do(func() {
+			// restoring the descriptor closes the write end of the pipe
+			_ = dup2(saved, fd)
+			_ = syscall.Close(saved)
+			cerr = <-e
+		})
+		return cerr
+	}
+
+	return release, nil
+}
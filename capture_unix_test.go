@@ -0,0 +1,195 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package capture
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestOutputFD(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	stat := map[int]*syscall.Stat_t{1: {}, 2: {}}
+	for fd, st := range stat {
+		if err := syscall.Fstat(fd, st); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// ACT
+	stdout, stderr, err := OutputFD(func() error {
+		_, _ = syscall.Write(1, []byte("to fd 1\n"))
+		_, _ = syscall.Write(2, []byte("to fd 2\n"))
+		fmt.Println("to os.Stdout")
+		os.Stderr.WriteString("to os.Stderr")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout captured", func(t *testing.T) {
+		wanted := []string{"to fd 1", "to os.Stdout"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stderr captured", func(t *testing.T) {
+		wanted := []string{"to fd 2", "to os.Stderr"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("file descriptors restored", func(t *testing.T) {
+		for _, fd := range []int{1, 2} {
+			wanted := stat[fd]
+			got := syscall.Stat_t{}
+			if err := syscall.Fstat(fd, &got); err != nil {
+				t.Fatal(err)
+			}
+			if wanted.Dev != got.Dev || wanted.Ino != got.Ino {
+				t.Errorf("fd %d: not restored", fd)
+			}
+		}
+	})
+
+	t.Run("with flush", func(t *testing.T) {
+		// ARRANGE
+		w := bufio.NewWriter(os.Stdout)
+		cf := &countFlusher{}
+
+		// ACT
+		stdout, _, err := OutputFD(func() error {
+			_, _ = w.WriteString("buffered\n")
+			return nil
+		}, WithFlush(w, cf))
+
+		// ASSERT
+		t.Run("buffered output captured", func(t *testing.T) {
+			wanted := []string{"buffered"}
+			got := stdout
+			if err != nil || !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %v\ngot   : %v (error: %v)", wanted, got, err)
+			}
+		})
+
+		t.Run("flushed before and after", func(t *testing.T) {
+			wanted := 2
+			got := cf.calls
+			if wanted != got {
+				t.Errorf("\nwanted: %d\ngot   : %d", wanted, got)
+			}
+		})
+	})
+
+	t.Run("when error copying captured buffers", func(t *testing.T) {
+		// ARRANGE
+		cpyerr := fmt.Errorf("copy error")
+		cp := func(dst io.Writer, src io.Reader) (int64, error) { _, _ = io.Copy(dst, src); return 0, cpyerr }
+
+		// ACT
+		stdout, _, err := OutputFD(func() error { _, _ = syscall.Write(1, []byte("output")); return nil }, WithCopyFunc(cp))
+
+		// ASSERT
+		t.Run("errors", func(t *testing.T) {
+			got := err
+
+			wanted := ErrStdoutCapture
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+
+			wanted = ErrStderrCapture
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("stdout is nil", func(t *testing.T) {
+			got := stdout
+			if got != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", got)
+			}
+		})
+	})
+}
+
+func TestOutputFDRuntimeOutput(t *testing.T) {
+	// ARRANGE
+	var inner []string
+
+	// ACT
+	_, stderr, err := OutputFD(func() error {
+		_, inner, _ = Output(func() error {
+			println("written by the runtime")
+			return nil
+		})
+		return nil
+	})
+
+	// ASSERT
+	t.Run("returns no error", func(t *testing.T) {
+		got := err
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+
+	t.Run("not captured by Output", func(t *testing.T) {
+		got := inner
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+
+	t.Run("captured by OutputFD", func(t *testing.T) {
+		wanted := []string{"written by the runtime"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+}
+
+func TestWithDrainTimeoutWhenPipeHeldOpen(t *testing.T) {
+	// ARRANGE
+	var held int
+
+	// ACT
+	stdout, _, err := Output(func() error {
+		fmt.Println("before timeout")
+
+		// a duplicate of the write end of the pipe (as inherited by a
+		// child process) holds the pipe open once the capture is closed
+		var err error
+		held, err = syscall.Dup(int(os.Stdout.Fd()))
+		return err
+	}, WithDrainTimeout(10*time.Millisecond))
+	_ = syscall.Close(held)
+
+	// ASSERT
+	if !errors.Is(err, ErrDrainTimeout) {
+		t.Errorf("error:\nwanted: %v\ngot   : %v", ErrDrainTimeout, err)
+	}
+	if wanted, got := []string{"before timeout"}, stdout; !reflect.DeepEqual(wanted, got) {
+		t.Errorf("stdout:\nwanted: %q\ngot   : %q", wanted, got)
+	}
+}
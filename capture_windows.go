@@ -0,0 +1,85 @@
+//go:build windows
+
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"syscall"
+)
+
+// SetStdHandle is not provided by the syscall package.
+var procSetStdHandle = syscall.NewLazyDLL("kernel32.dll").NewProc("SetStdHandle")
+
+// setStdHandle replaces the process standard handle identified by std
+// (e.g. syscall.STD_OUTPUT_HANDLE).
+func setStdHandle(std int, h syscall.Handle) error {
+	if r, _, err := procSetStdHandle.Call(uintptr(uint32(std)), uintptr(h)); r == 0 {
+		return err
+	}
+	return nil
+}
+
+// captureFD redirects the standard handle corresponding to a file
+// descriptor (1 for stdout, 2 for stderr) to a pipe, copying any output
+// written using the handle to the supplied writer using the supplied
+// copy function.
+//
+// The returned function restores the original handle and waits for all
+// captured output to be copied, returning any error that occurred while
+// copying.  The function may be called more than once; only the first
+// call has any effect.
+func captureFD(fd int, dst io.Writer, cp copyFunc) (func() error, error) {
+	var std int
+	switch fd {
+	case 1:
+		std = syscall.STD_OUTPUT_HANDLE
+	case 2:
+		std = syscall.STD_ERROR_HANDLE
+	default:
+		return nil, errors.ErrUnsupported
+	}
+
+	saved, err := syscall.GetStdHandle(std)
+	if err != nil {
+		return nil, err
+	}
+
+	r, w, err := pipeFn()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPipeCreate, err)
+	}
+
+	if err := setStdHandle(std, syscall.Handle(w.Fd())); err != nil {
+		_ = r.Close()
+		_ = w.Close()
+		return nil, err
+	}
+
+	e := make(chan error)
+	go func() {
+		defer r.Close()
+		_, err := cp(dst, r)
+		e <- err
+	}()
+
+	var (
+		once sync.Once
+		cerr error
+	)
+	release := func() error {
+		once.Do(func() {
+			// unlike a unix descriptor the handle refers to the write end of
+			// the pipe itself, which must be closed once the original handle
+			// has been restored
+			_ = setStdHandle(std, saved)
+			_ = w.Close()
+			cerr = <-e
+		})
+		return cerr
+	}
+
+	return release, nil
+}
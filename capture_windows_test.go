@@ -0,0 +1,79 @@
+//go:build windows
+
+package capture
+
+import (
+	"errors"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+// writeStdHandle writes to the process standard handle identified by
+// std, as C code or the Win32 console API would, bypassing os.Stdout
+// and os.Stderr.
+func writeStdHandle(std int, s string) {
+	h, err := syscall.GetStdHandle(std)
+	if err != nil {
+		return
+	}
+	var n uint32
+	_ = syscall.WriteFile(h, []byte(s), &n, nil)
+}
+
+func TestOutputFD(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	saved := map[int]syscall.Handle{}
+	for _, std := range []int{syscall.STD_OUTPUT_HANDLE, syscall.STD_ERROR_HANDLE} {
+		h, err := syscall.GetStdHandle(std)
+		if err != nil {
+			t.Fatal(err)
+		}
+		saved[std] = h
+	}
+
+	// ACT
+	stdout, stderr, err := OutputFD(func() error {
+		writeStdHandle(syscall.STD_OUTPUT_HANDLE, "to stdout handle\n")
+		writeStdHandle(syscall.STD_ERROR_HANDLE, "to stderr handle\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout captured", func(t *testing.T) {
+		wanted := []string{"to stdout handle"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stderr captured", func(t *testing.T) {
+		wanted := []string{"to stderr handle"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("handles restored", func(t *testing.T) {
+		for std, wanted := range saved {
+			got, err := syscall.GetStdHandle(std)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if wanted != got {
+				t.Errorf("handle %d: not restored", std)
+			}
+		}
+	})
+}
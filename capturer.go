@@ -0,0 +1,317 @@
+package capture
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Capturer captures stdout and stderr output between calls to Start
+// and Stop, for situations where the output to be captured is not
+// conveniently produced by a single function.
+//
+// The zero value is ready to use.  A Capturer may be reused; once
+// stopped, it may be started again.  A Capturer is not safe for
+// concurrent use; Start and Stop should be called from the same
+// goroutine.
+//
+// As with all captures, a Capturer replaces the process-global
+// os.Stdout and os.Stderr; while started, any other capture on a
+// different goroutine will block until the Capturer is stopped.  This
+// includes a subtest run using t.Run, so a subtest that captures output
+// deadlocks if run while the Capturer is started.
+//
+// Example:
+//
+//	  func TestSomething(t *testing.T) {
+//		c := &capture.Capturer{}
+//		if err := c.Start(); err != nil {
+//		   t.Fatal(err)
+//		}
+//		defer c.Reset()
+//
+//		fmt.Println("some output")
+//		doSomething()
+//
+//		stdout, stderr, err := c.Stop()
+//		...
+//	  }
+type Capturer struct {
+	stdout        *bytes.Buffer
+	stderr        *bytes.Buffer
+	restoreStdout func()
+	restoreStderr func()
+	closeStdout   func() error
+	closeStderr   func() error
+	pipeStdout    *os.File
+	pipeStderr    *os.File
+	markStdout    *markWriter
+	markStderr    *markWriter
+	copiedStdout  chan struct{}
+	copiedStderr  chan struct{}
+	hook          *hook
+	started       bool
+}
+
+// Start starts capturing stdout and stderr.  If the Capturer has
+// already been started (and not yet stopped), ErrAlreadyStarted is
+// returned.
+//
+// If a pipe cannot be created for either stream, neither stream is
+// captured (os.Stdout and os.Stderr are left unchanged), the Capturer
+// is not started and ErrStdoutCapture or ErrStderrCapture is returned,
+// wrapping ErrPipeCreate.
+func (c *Capturer) Start() error {
+	if c.started {
+		return ErrAlreadyStarted
+	}
+
+	// the capture lock is held by the captures of each stream until the
+	// Capturer is stopped; it is also held while starting, so that the
+	// hooks are serialized with any other capture
+	mu.lock()
+	defer mu.unlock()
+
+	h := startHook()
+
+	stdout := &bytes.Buffer{}
+	markStdout := &markWriter{w: h.tee(StdoutStream, stdout)}
+	copiedStdout := make(chan struct{})
+	restoreStdout, closeStdout, err := capture(&os.Stdout, markStdout, signalCopy(copiedStdout), 0)
+	if err != nil {
+		restoreStdout()
+		err = fmt.Errorf("%w: %w", ErrStdoutCapture, err)
+		h.stop(err)
+		return err
+	}
+
+	stderr := &bytes.Buffer{}
+	markStderr := &markWriter{w: h.tee(StderrStream, stderr)}
+	copiedStderr := make(chan struct{})
+	restoreStderr, closeStderr, err := capture(&os.Stderr, markStderr, signalCopy(copiedStderr), 0)
+	if err != nil {
+		restoreStderr()
+		restoreStdout()
+		err = fmt.Errorf("%w: %w", ErrStderrCapture, err)
+		h.stop(err)
+		return err
+	}
+
+	c.stdout, c.restoreStdout, c.closeStdout = stdout, restoreStdout, closeStdout
+	c.stderr, c.restoreStderr, c.closeStderr = stderr, restoreStderr, closeStderr
+	c.pipeStdout, c.markStdout, c.copiedStdout = os.Stdout, markStdout, copiedStdout
+	c.pipeStderr, c.markStderr, c.copiedStderr = os.Stderr, markStderr, copiedStderr
+	c.hook = h
+	c.started = true
+
+	return nil
+}
+
+// Stop stops capturing, restoring os.Stdout and os.Stderr, and returns
+// the output captured since the Capturer was started.
+//
+// If the Capturer has not been started, ErrNotStarted is returned.
+// Errors capturing output are handled as for Output: ErrStdoutCapture
+// and/or ErrStderrCapture are returned and the corresponding captured
+// output is discarded.
+func (c *Capturer) Stop() ([]string, []string, error) {
+	if !c.started {
+		return nil, nil, ErrNotStarted
+	}
+
+	o := c.stop()
+
+	return lines(string(captured(c.stdout, o.stdout))),
+		lines(string(captured(c.stderr, o.stderr))),
+		o.join()
+}
+
+// Checkpoint returns the output captured since the Capturer was
+// started or since the previous call to Checkpoint, without stopping
+// the capture.  This allows the output of each phase of a multi-phase
+// process to be asserted separately.
+//
+// To ensure that all output written before the checkpoint is returned,
+// the capture is completed and a new capture immediately started (with
+// no other capture able to start in between).  os.Stdout and os.Stderr
+// are therefore replaced by new pipes; a reference to either obtained
+// before the checkpoint (e.g. held by a background goroutine) is no
+// longer usable.
+//
+// If the Capturer has not been started, ErrNotStarted is returned.
+// Errors capturing output are handled as for Stop; the capture
+// continues regardless unless it cannot be restarted, in which case the
+// error returned by Start is also returned and the Capturer is left
+// stopped.
+//
+// Example:
+//
+//	  func TestPhases(t *testing.T) {
+//		c := &capture.Capturer{}
+//		_ = c.Start()
+//		defer c.Reset()
+//
+//		phaseOne()
+//		stdout, _, _ := c.Checkpoint() // output of phase one
+//
+//		phaseTwo()
+//		stdout, _, _ = c.Stop() // output of phase two
+//		...
+//	  }
+func (c *Capturer) Checkpoint() ([]string, []string, error) {
+	if !c.started {
+		return nil, nil, ErrNotStarted
+	}
+
+	// the lock is held while the capture is restarted, so that no other
+	// capture can start in between
+	mu.lock()
+	defer mu.unlock()
+
+	stdout, stderr, err := c.Stop()
+	if serr := c.Start(); serr != nil {
+		err = errors.Join(err, serr)
+	}
+
+	return stdout, stderr, err
+}
+
+// Flush calls the Flush method of each of the supplied flushers (e.g.
+// a bufio.Writer or an asynchronous logger), in the order supplied,
+// without stopping the capture.  Any output written by the flushers to
+// the captured os.Stdout or os.Stderr is captured, and is returned by
+// Stop (or Checkpoint).  Any errors returned by the flushers are
+// joined and returned.
+//
+// This allows a test to determine when output buffered by deferred or
+// asynchronous writers is complete: Flush may be called once the work
+// producing the output has settled, before calling Stop.  Unlike
+// OutputWait, which allows a fixed grace period for output to be
+// written after a function has returned, completion does not depend on
+// timing; output that a writer has not yet written when it is flushed
+// is not captured.
+//
+// Flush returns once all output written to the captured os.Stdout and
+// os.Stderr before it returns (including that written by the
+// flushers) has been captured, so that a following Checkpoint or Stop
+// is guaranteed to return it.  It does so by writing a random marker
+// to each capture pipe after the flushers are flushed, and waiting
+// until the marker has been read from the pipe; the marker is removed
+// from the captured output.  Output written concurrently by other
+// goroutines while Flush is called may or may not be captured by then.
+// If output is no longer being copied from a pipe (e.g. the copy
+// failed), Flush does not wait; the error returned wraps ErrCopyStopped
+// (and ErrStdoutCapture or ErrStderrCapture).
+//
+// Flush may be called any number of times while the Capturer is
+// started; each call flushes each of the supplied flushers.  If the
+// Capturer has not been started, ErrNotStarted is returned and no
+// flusher is called.
+//
+// Example:
+//
+//	  func TestAsyncLogging(t *testing.T) {
+//		c := &capture.Capturer{}
+//		_ = c.Start()
+//		defer c.Reset()
+//
+//		w := bufio.NewWriter(os.Stdout) // the captured os.Stdout
+//		doSomething(w)
+//
+//		if err := c.Flush(w); err != nil {
+//		   t.Fatal(err)
+//		}
+//		stdout, _, err := c.Stop()
+//		...
+//	  }
+func (c *Capturer) Flush(flushers ...interface{ Flush() error }) error {
+	if !c.started {
+		return ErrNotStarted
+	}
+
+	err := flush(flushers, func() error { return nil })()
+
+	// a random marker is written to each pipe after the flushers, and
+	// Flush waits until it has been copied from the pipe, so that all
+	// output written ahead of it has been captured
+	marker := make([]byte, 16)
+	if _, rerr := rand.Read(marker); rerr != nil {
+		return errors.Join(err, rerr)
+	}
+	if serr := syncPipe(c.pipeStdout, c.markStdout, c.copiedStdout, marker); serr != nil {
+		err = errors.Join(err, fmt.Errorf("%w: %w", ErrStdoutCapture, serr))
+	}
+	if serr := syncPipe(c.pipeStderr, c.markStderr, c.copiedStderr, marker); serr != nil {
+		err = errors.Join(err, fmt.Errorf("%w: %w", ErrStderrCapture, serr))
+	}
+	return err
+}
+
+// syncPipe writes a marker to a capture pipe, waiting until it has been
+// copied from the pipe and removed by the markWriter receiving the
+// output copied from the pipe.  If the copy from the pipe has stopped
+// (the copied channel is closed) before the marker is copied,
+// ErrCopyStopped is returned.
+func syncPipe(pipe *os.File, m *markWriter, copied <-chan struct{}, marker []byte) error {
+	seen := m.expect(marker)
+	if _, err := pipe.Write(marker); err != nil {
+		return errors.Join(err, m.cancel())
+	}
+
+	select {
+	case <-seen:
+		return nil
+	case <-copied:
+		// the marker may have been copied before the copy stopped
+		select {
+		case <-seen:
+			return nil
+		default:
+			return errors.Join(ErrCopyStopped, m.cancel())
+		}
+	}
+}
+
+// signalCopy returns a copy function that copies using io.Copy, closing
+// the supplied channel when the copy has stopped.
+func signalCopy(copied chan struct{}) copyFunc {
+	return func(dst io.Writer, src io.Reader) (int64, error) {
+		defer close(copied)
+		return io.Copy(dst, src)
+	}
+}
+
+// Reset stops any capture in progress, discarding any captured output,
+// and returns the Capturer to its initial state.  Reset may be called
+// (e.g. deferred) whether or not the Capturer has been started.
+func (c *Capturer) Reset() {
+	if c.started {
+		_ = c.stop()
+	}
+	*c = Capturer{}
+}
+
+// stop closes the capture pipes and restores os.Stdout and os.Stderr,
+// calling any OnStop hook.
+func (c *Capturer) stop() outcome {
+	mu.lock()
+	defer mu.unlock()
+
+	o := outcome{}
+	if err := c.closeStdout(); err != nil {
+		o.stdout = fmt.Errorf("%w: %w", ErrStdoutCapture, err)
+	}
+	if err := c.closeStderr(); err != nil {
+		o.stderr = fmt.Errorf("%w: %w", ErrStderrCapture, err)
+	}
+
+	c.restoreStderr()
+	c.restoreStdout()
+	c.started = false
+	c.hook.stop(o.join())
+
+	return o
+}
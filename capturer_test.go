@@ -0,0 +1,370 @@
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCapturer(t *testing.T) {
+	// ARRANGE
+	ogout, ogerr := os.Stdout, os.Stderr
+	c := &Capturer{}
+
+	// ACT
+	starterr := c.Start()
+	fmt.Println("to stdout")
+	os.Stderr.WriteString("to stderr")
+	restarterr := c.Start()
+	stdout, stderr, err := c.Stop()
+
+	// ASSERT
+	t.Run("start", func(t *testing.T) {
+		got := starterr
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+
+	t.Run("start when already started", func(t *testing.T) {
+		wanted := ErrAlreadyStarted
+		got := restarterr
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		t.Run("no error", func(t *testing.T) {
+			got := err
+			if got != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", got)
+			}
+		})
+
+		t.Run("output captured", func(t *testing.T) {
+			wanted := [][]string{{"to stdout"}, {"to stderr"}}
+			got := [][]string{stdout, stderr}
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+
+		t.Run("streams restored", func(t *testing.T) {
+			if os.Stdout != ogout || os.Stderr != ogerr {
+				t.Errorf("os.Stdout and/or os.Stderr not restored")
+			}
+		})
+	})
+
+	t.Run("stop when not started", func(t *testing.T) {
+		// ACT
+		_, _, err := c.Stop()
+
+		// ASSERT
+		wanted := ErrNotStarted
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		// ACT
+		_ = c.Start()
+		fmt.Println("restarted")
+		stdout, _, _ := c.Stop()
+
+		// ASSERT
+		wanted := []string{"restarted"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		// ACT
+		_ = c.Start()
+		fmt.Println("discarded")
+		c.Reset()
+
+		// ASSERT
+		t.Run("streams restored", func(t *testing.T) {
+			if os.Stdout != ogout || os.Stderr != ogerr {
+				t.Errorf("os.Stdout and/or os.Stderr not restored")
+			}
+		})
+
+		t.Run("stopped", func(t *testing.T) {
+			_, _, err := c.Stop()
+			wanted := ErrNotStarted
+			got := err
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+	})
+}
+
+func TestCapturerCheckpoint(t *testing.T) {
+	t.Run("when not started", func(t *testing.T) {
+		// ARRANGE
+		c := &Capturer{}
+
+		// ACT
+		_, _, err := c.Checkpoint()
+
+		// ASSERT
+		wanted := ErrNotStarted
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("phases", func(t *testing.T) {
+		// ARRANGE
+		og := os.Stdout
+		c := &Capturer{}
+		_ = c.Start()
+		defer c.Reset()
+
+		// ACT
+		fmt.Println("phase one")
+		os.Stderr.WriteString("phase one error\n")
+		out1, err1, cperr := c.Checkpoint()
+		capturing := os.Stdout != og
+
+		out2, _, _ := c.Checkpoint()
+
+		fmt.Println("phase three")
+		out3, err3, stoperr := c.Stop()
+
+		// ASSERT
+		if cperr != nil || stoperr != nil {
+			t.Errorf("unexpected errors: %v, %v", cperr, stoperr)
+		}
+		if !capturing {
+			t.Error("capture not continued after checkpoint")
+		}
+		wanted := [][]string{{"phase one"}, {"phase one error"}, nil, {"phase three"}, nil}
+		got := [][]string{out1, err1, out2, out3, err3}
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		if os.Stdout != og {
+			t.Error("stdout not restored")
+		}
+	})
+}
+
+func TestCapturerFlush(t *testing.T) {
+	t.Run("when not started", func(t *testing.T) {
+		// ARRANGE
+		c := &Capturer{}
+		f := &countFlusher{}
+
+		// ACT
+		err := c.Flush(f)
+
+		// ASSERT
+		wanted := ErrNotStarted
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+		if f.calls != 0 {
+			t.Errorf("\nwanted: 0 calls\ngot   : %d calls", f.calls)
+		}
+	})
+
+	t.Run("flushes buffered output", func(t *testing.T) {
+		// ARRANGE
+		c := &Capturer{}
+		_ = c.Start()
+		defer c.Reset()
+		w := bufio.NewWriter(stdoutWriter{})
+
+		// ACT
+		fmt.Fprintln(w, "first")
+		err1 := c.Flush(w)
+		fmt.Fprintln(w, "second")
+		err2 := c.Flush(w)
+		err3 := c.Flush(w)
+		fmt.Fprintln(w, "not flushed")
+		stdout, _, err := c.Stop()
+		w.Reset(stdoutWriter{}) // discards the unflushed output
+
+		// ASSERT
+		if err1 != nil || err2 != nil || err3 != nil || err != nil {
+			t.Errorf("unexpected errors: %v, %v, %v, %v", err1, err2, err3, err)
+		}
+		wanted := []string{"first", "second"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("waits until flushed output is captured", func(t *testing.T) {
+		// ARRANGE
+		c := &Capturer{}
+		_ = c.Start()
+		defer c.Reset()
+		w := bufio.NewWriter(stdoutWriter{})
+		output := strings.Repeat("flushed\n", 10000)
+
+		// ACT
+		fmt.Fprint(w, output)
+		err := c.Flush(w)
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		wanted := len(output)
+		got := c.stdout.Len()
+		if wanted != got {
+			t.Errorf("\nwanted: %d bytes\ngot   : %d bytes", wanted, got)
+		}
+	})
+
+	t.Run("returns flusher errors", func(t *testing.T) {
+		// ARRANGE
+		c := &Capturer{}
+		_ = c.Start()
+		defer c.Reset()
+		ferr1 := errors.New("flush error 1")
+		ferr2 := errors.New("flush error 2")
+		f := &countFlusher{}
+
+		// ACT
+		err := c.Flush(flushError{ferr1}, f, flushError{ferr2})
+
+		// ASSERT
+		for _, wanted := range []error{ferr1, ferr2} {
+			if !errors.Is(err, wanted) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, err)
+			}
+		}
+		if f.calls != 1 {
+			t.Errorf("\nwanted: 1 call\ngot   : %d calls", f.calls)
+		}
+	})
+}
+
+func TestSyncPipe(t *testing.T) {
+	t.Run("when the copy has stopped", func(t *testing.T) {
+		// ARRANGE
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		defer w.Close()
+		m := &markWriter{w: &bytes.Buffer{}}
+		copied := make(chan struct{})
+		close(copied) // nothing is copied from r
+
+		// ACT
+		err = syncPipe(w, m, copied, []byte("<mark>"))
+
+		// ASSERT
+		wanted := ErrCopyStopped
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("when the marker is copied", func(t *testing.T) {
+		// ARRANGE
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer w.Close()
+		buf := &bytes.Buffer{}
+		m := &markWriter{w: buf}
+		copied := make(chan struct{})
+		go func() {
+			defer close(copied)
+			defer r.Close()
+			_, _ = io.CopyN(m, r, int64(len("<mark>")))
+		}()
+
+		// ACT
+		err = syncPipe(w, m, copied, []byte("<mark>"))
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", err)
+		}
+	})
+}
+
+// countFlusher is a flusher that counts the calls to its Flush method.
+type countFlusher struct{ calls int }
+
+func (f *countFlusher) Flush() error { f.calls++; return nil }
+
+func TestCapturerStartError(t *testing.T) {
+	// ARRANGE
+	pipeerr := errors.New("too many open files")
+	og := pipeFn
+	defer func() { pipeFn = og }()
+	ogout, ogerr := os.Stdout, os.Stderr
+
+	testcases := []struct {
+		name     string
+		fail     int
+		sentinel error
+	}{
+		{name: "when the stdout pipe cannot be created", fail: 1, sentinel: ErrStdoutCapture},
+		{name: "when the stderr pipe cannot be created", fail: 2, sentinel: ErrStderrCapture},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ARRANGE
+			calls := 0
+			pipeFn = func() (*os.File, *os.File, error) {
+				if calls++; calls == tc.fail {
+					return nil, nil, pipeerr
+				}
+				return og()
+			}
+			c := &Capturer{}
+			defer c.Reset()
+
+			// ACT
+			err := c.Start()
+			pipeFn = og
+
+			// ASSERT
+			for _, wanted := range []error{tc.sentinel, ErrPipeCreate, pipeerr} {
+				if !errors.Is(err, wanted) {
+					t.Errorf("\nwanted: %v\ngot   : %v", wanted, err)
+				}
+			}
+			if os.Stdout != ogout || os.Stderr != ogerr {
+				t.Error("os.Stdout and/or os.Stderr not restored")
+			}
+			if _, _, err := c.Stop(); !errors.Is(err, ErrNotStarted) {
+				t.Errorf("\nwanted: %v\ngot   : %v", ErrNotStarted, err)
+			}
+
+			// the capture lock is released
+			stdout, _, err := Output(func() error { fmt.Println("output"); return nil })
+			if err != nil || !reflect.DeepEqual([]string{"output"}, stdout) {
+				t.Errorf("\nwanted: [output], <nil>\ngot   : %v, %v", stdout, err)
+			}
+		})
+	}
+}
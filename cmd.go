@@ -0,0 +1,52 @@
+package capture
+
+import (
+	"os"
+	"os/exec"
+)
+
+// OutputCmd captures the stdout and stderr output of a command run by a
+// supplied function, together with any stdout and stderr output
+// produced by the function itself, as for Output.  If fn is nil, the
+// command is run using cmd.Run.
+//
+// A child process writes its output to the files with which it was
+// started, not to os.Stdout or os.Stderr; Output therefore captures the
+// output of a command only if the Stdout and Stderr of the command are
+// set to os.Stdout and os.Stderr while the capture is in progress (when
+// they refer to the capture pipes).  A command whose Stdout or Stderr
+// was set to os.Stdout or os.Stderr beforehand (or left nil, in which
+// case output is discarded) writes to the original file (or nowhere).
+//
+// OutputCmd sets the Stdout and Stderr of the command to the captured
+// os.Stdout and os.Stderr before calling the function, replacing any
+// existing Stdout and Stderr, so that the output of the command is
+// captured regardless.  The function must start the command (and
+// should wait for it to complete) before returning; output written by
+// the command after the capture is complete is not captured.
+//
+// A command that starts a long-running process of its own (which
+// inherits the capture pipes) may prevent the capture from completing
+// until that process exits; use Output with WithDrainTimeout (with the
+// Stdout and Stderr of the command set as described) if this is a
+// concern.
+//
+// Error handling is identical to Output.
+//
+// Example:
+//
+//	  func TestVersion(t *testing.T) {
+//		cmd := exec.Command("app", "--version")
+//		stdout, _, err := capture.OutputCmd(cmd, nil)
+//		...
+//	  }
+func OutputCmd(cmd *exec.Cmd, fn func() error) ([]string, []string, error) {
+	if fn == nil {
+		fn = cmd.Run
+	}
+	return Output(func() error {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return fn()
+	})
+}
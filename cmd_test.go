@@ -0,0 +1,82 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+// TestCmdHelper is not a test; it is run as the command executed by
+// TestOutputCmd, writing output to stdout and stderr.
+func TestCmdHelper(t *testing.T) {
+	if os.Getenv("CAPTURE_CMD_HELPER") != "1" {
+		return
+	}
+	fmt.Println("child stdout")
+	fmt.Fprintln(os.Stderr, "child stderr")
+	os.Exit(3)
+}
+
+// helperCmd returns a command running TestCmdHelper.
+func helperCmd() *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCmdHelper$")
+	cmd.Env = append(os.Environ(), "CAPTURE_CMD_HELPER=1")
+	return cmd
+}
+
+func TestOutputCmd(t *testing.T) {
+	t.Run("runs command", func(t *testing.T) {
+		// ACT
+		stdout, stderr, err := OutputCmd(helperCmd(), nil)
+
+		// ASSERT
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+			t.Errorf("error:\nwanted: exit status 3\ngot   : %v", err)
+		}
+		if wanted, got := []string{"child stdout"}, stdout; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("stdout:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		if wanted, got := []string{"child stderr"}, stderr; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("stderr:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("with function", func(t *testing.T) {
+		// ARRANGE
+		cmd := helperCmd()
+		cmd.Stdout = os.Stdout // the original stdout, replaced by OutputCmd
+
+		// ACT
+		stdout, _, _ := OutputCmd(cmd, func() error {
+			fmt.Println("before")
+			_ = cmd.Run()
+			fmt.Println("after")
+			return nil
+		})
+
+		// ASSERT
+		wanted := []string{"before", "child stdout", "after"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("command configured before capture", func(t *testing.T) {
+		// ARRANGE
+		cmd := helperCmd()
+		cmd.Stdout = nil // output is discarded, not captured by Output
+
+		// ACT
+		stdout, _, _ := Output(func() error { _ = cmd.Run(); return nil })
+
+		// ASSERT
+		if stdout != nil {
+			t.Errorf("\nwanted: nil\ngot   : %q", stdout)
+		}
+	})
+}
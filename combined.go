@@ -0,0 +1,85 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Combined captures the stdout and stderr output produced during
+// execution of a supplied function as a single, ordered stream.
+//
+// Both os.Stdout and os.Stderr are redirected to the same pipe, so
+// output is captured in the order in which it is written.  Ordering is
+// best-effort: output written through a buffering writer is captured
+// when that writer is flushed, not when its content is produced.  For
+// the common case of unbuffered, line-at-a-time output (e.g. fmt.Println
+// or os.Stderr.WriteString) the ordering is deterministic.
+//
+// If the supplied function returns an error, the error is returned
+// together with any captured output.
+//
+// If an error occurs while capturing the output, both ErrStdoutCapture
+// and ErrStderrCapture are returned (wrapped with any error returned from
+// the supplied function) and any captured output is discarded.
+//
+// Of the available options, only WithCopyFunc and WithReadChunkSize
+// apply to Combined; any other options are ignored.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		output, err := capture.Combined(func () error {
+//		   fmt.Println("to stdout")
+//		   fmt.Fprintln(os.Stderr, "to stderr")
+//		   return nil
+//		})
+//
+//		fmt.Printf("output: %v", output) // [to stdout to stderr]
+//		fmt.Printf("error: %v", err)
+//	  }
+func Combined(fn func() error, opts ...Option) ([]string, error) {
+	output, err := combined(newConfig(opts).copier(), fn)
+	return lines(string(output)), err
+}
+
+// combined captures the stdout and stderr output produced during
+// execution of a supplied function as a single stream, as for Combined,
+// returning the captured output verbatim.  Output is copied using the
+// supplied copy function.
+func combined(cp copyFunc, fn func() error) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	err, cerr := combineTo(buf, cp, fn)
+
+	return captured(buf, cerr), errors.Join(err, cerr)
+}
+
+// combineTo captures the stdout and stderr output produced during
+// execution of a supplied function as a single stream, copied to the
+// supplied writer using the supplied copy function.  The error returned
+// by the function and any error capturing the output (wrapped with both
+// ErrStdoutCapture and ErrStderrCapture) are returned.
+func combineTo(dst io.Writer, cp copyFunc, fn func() error) (error, error) {
+	mu.lock()
+	defer mu.unlock()
+
+	h := startHook()
+
+	// os.Stderr is redirected to the same pipe as os.Stdout, and both are
+	// restored before the pipe is closed
+	restore, close, _ := capture(&os.Stdout, h.tee(StdoutStream, dst), cp, 0, &os.Stderr)
+	defer restore()
+
+	err := fn()
+
+	cerr := close()
+	if cerr != nil {
+		cerr = fmt.Errorf("%w: %w: %w", ErrStdoutCapture, ErrStderrCapture, cerr)
+	}
+	h.stop(errors.Join(err, cerr))
+
+	return err, cerr
+}
@@ -0,0 +1,81 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestCombined(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	stderr := os.Stderr
+
+	// ACT
+	output, err := Combined(func() error {
+		fmt.Println("to stdout (1)")
+		os.Stderr.WriteString("to stderr (1)\n")
+		fmt.Println("to stdout (2)")
+		os.Stderr.WriteString("to stderr (2)")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("output captured in order", func(t *testing.T) {
+		wanted := []string{"to stdout (1)", "to stderr (1)", "to stdout (2)", "to stderr (2)"}
+		got := output
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stderr restored", func(t *testing.T) {
+		wanted := stderr
+		got := os.Stderr
+		if wanted != got {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("when error copying captured buffer", func(t *testing.T) {
+		// ARRANGE
+		cpyerr := fmt.Errorf("copy error")
+		cp := func(dst io.Writer, src io.Reader) (int64, error) { _, _ = io.Copy(dst, src); return 0, cpyerr }
+
+		// ACT
+		output, err := Combined(func() error { fmt.Println("some output"); return nil }, WithCopyFunc(cp))
+
+		// ASSERT
+		t.Run("errors", func(t *testing.T) {
+			got := err
+
+			wanted := ErrStdoutCapture
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+
+			wanted = ErrStderrCapture
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("output is nil", func(t *testing.T) {
+			got := output
+			if got != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", got)
+			}
+		})
+	})
+}
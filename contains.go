@@ -0,0 +1,81 @@
+package capture
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// OutputContains captures the combined stdout and stderr output
+// produced during execution of a supplied function (as for Combined)
+// and reports whether every one of the supplied substrings appears
+// somewhere in the captured output.  Matching is case-sensitive.
+//
+// The captured lines are also returned, for further inspection,
+// together with any error.
+//
+// Example:
+//
+//	  func TestSomething(t *testing.T) {
+//		ok, output, _ := capture.OutputContains(func () error {
+//		   return doSomething()
+//		}, "started", "finished")
+//
+//		if !ok {
+//		   t.Errorf("unexpected output: %v", output)
+//		}
+//	  }
+func OutputContains(fn func() error, substrings ...string) (bool, []string, error) {
+	return outputContains(fn, substrings, strings.Contains)
+}
+
+// OutputContainsFold is the same as OutputContains, except that matching
+// is case-insensitive: a substring matches if it is equal to some part
+// of the output under Unicode case folding (as for strings.EqualFold).
+func OutputContainsFold(fn func() error, substrings ...string) (bool, []string, error) {
+	return outputContains(fn, substrings, containsFold)
+}
+
+// outputContains captures combined output and reports whether all
+// substrings are present, as determined by the supplied function.
+func outputContains(fn func() error, substrings []string, contains func(s, sub string) bool) (bool, []string, error) {
+	output, err := Combined(fn)
+
+	s := strings.Join(output, "\n")
+	for _, sub := range substrings {
+		if !contains(s, sub) {
+			return false, output, err
+		}
+	}
+
+	return true, output, err
+}
+
+// containsFold reports whether sub is equal, under Unicode case folding,
+// to some part of s.  Case folding maps each rune to a single rune, so
+// only parts of s with the same number of runes as sub are compared.
+func containsFold(s, sub string) bool {
+	n := utf8.RuneCountInString(sub)
+
+	// end is the offset of the end of the n runes starting at i
+	end := 0
+	for k := 0; k < n; k++ {
+		if end == len(s) {
+			return false
+		}
+		_, size := utf8.DecodeRuneInString(s[end:])
+		end += size
+	}
+
+	for i := 0; ; {
+		if strings.EqualFold(s[i:end], sub) {
+			return true
+		}
+		if end == len(s) {
+			return false
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		_, size = utf8.DecodeRuneInString(s[end:])
+		end += size
+	}
+}
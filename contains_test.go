@@ -0,0 +1,95 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputContains(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	fn := func() error {
+		fmt.Println("Started at 12:00:01")
+		os.Stderr.WriteString("Finished at 12:00:02\n")
+		return fnerr
+	}
+
+	testcases := []struct {
+		name       string
+		fn         func(func() error, ...string) (bool, []string, error)
+		substrings []string
+		result     bool
+	}{
+		{name: "contains/all present", fn: OutputContains, substrings: []string{"Started", "Finished"}, result: true},
+		{name: "contains/one missing", fn: OutputContains, substrings: []string{"Started", "Failed"}, result: false},
+		{name: "contains/different case", fn: OutputContains, substrings: []string{"started"}, result: false},
+		{name: "contains/no substrings", fn: OutputContains, result: true},
+		{name: "fold/different case", fn: OutputContainsFold, substrings: []string{"started", "FINISHED"}, result: true},
+		{name: "fold/one missing", fn: OutputContainsFold, substrings: []string{"started", "failed"}, result: false},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ACT
+			ok, output, err := tc.fn(fn, tc.substrings...)
+
+			// ASSERT
+			t.Run("result", func(t *testing.T) {
+				wanted := tc.result
+				got := ok
+				if wanted != got {
+					t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+				}
+			})
+
+			t.Run("output", func(t *testing.T) {
+				wanted := []string{"Started at 12:00:01", "Finished at 12:00:02"}
+				got := output
+				if !reflect.DeepEqual(wanted, got) {
+					t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+				}
+			})
+
+			t.Run("error", func(t *testing.T) {
+				wanted := fnerr
+				got := err
+				if !errors.Is(got, wanted) {
+					t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+				}
+			})
+		})
+	}
+}
+
+func TestContainsFold(t *testing.T) {
+	testcases := []struct {
+		s      string
+		sub    string
+		result bool
+	}{
+		{s: "Started", sub: "STARTED", result: true},
+		{s: "xyz", sub: "Y", result: true},
+		{s: "héllo", sub: "HÉL", result: true},
+		{s: "\u212aelvin", sub: "kelvin", result: true}, // KELVIN SIGN folds to k
+		{s: "\u017ftarted", sub: "START", result: true}, // LATIN SMALL LETTER LONG S folds to s
+		{s: "abc", sub: "", result: true},
+		{s: "", sub: "", result: true},
+		{s: "ab", sub: "abc", result: false},
+		{s: "abc", sub: "bd", result: false},
+	}
+	for _, tc := range testcases {
+		t.Run(fmt.Sprintf("%q in %q", tc.sub, tc.s), func(t *testing.T) {
+			// ACT
+			result := containsFold(tc.s, tc.sub)
+
+			// ASSERT
+			wanted := tc.result
+			got := result
+			if wanted != got {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+	}
+}
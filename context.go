@@ -0,0 +1,115 @@
+package capture
+
+import (
+	"context"
+	"time"
+)
+
+// OutputContext captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, returning early if
+// the supplied context is cancelled or its deadline is exceeded before
+// the function returns.
+//
+// The function is run in a separate goroutine.  If the context is done
+// before the function returns, capture is completed immediately:
+// os.Stdout and os.Stderr are restored and any output captured up to
+// that point is returned together with the context error (joined with
+// any capture errors).
+//
+// NOTE: the function is not (and cannot be) stopped when the context
+// is done; it may continue to run in the background after OutputContext
+// has returned.  Any output it produces after that point is not
+// captured.
+//
+// If the function panics, the panic is recovered on the goroutine
+// running the function and re-raised on the calling goroutine, after
+// os.Stdout and os.Stderr have been restored.  A panic occurring after
+// the context is done is recovered and discarded.
+//
+// Captures nested in the function (e.g. a call to Output) are supported
+// even though the function runs on a different goroutine.
+//
+// Any options are applied as for Output.  If os.Stdin is replaced
+// (WithStdin or WithStdinReader) and the context is done before the
+// function returns, the write end of the stdin pipe is closed, so that
+// a function blocked reading from os.Stdin (e.g. waiting for input at
+// an interactive prompt) reads io.EOF and is unblocked.  A function
+// reading from any other os.Stdin is not unblocked.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		ctx, cancel := context.WithTimeout(context.Background(), 2 * time.Second)
+//		defer cancel()
+//
+//		stdout, stderr, err := capture.OutputContext(ctx, func () error {
+//		   return doSomething()
+//		})
+//
+//		fmt.Printf("stdout: %v", stdout)
+//		fmt.Printf("stderr: %v", stderr)
+//		fmt.Printf("error: %v", err) // context.DeadlineExceeded if doSomething() took > 2s
+//	  }
+func OutputContext(ctx context.Context, fn func() error, opts ...Option) ([]string, []string, error) {
+	r := OutputResult(func() error {
+		done := make(chan result, 1)
+		mu.spawn(func() {
+			r := result{panicked: true}
+			defer func() {
+				if r.panicked {
+					r.value = recover()
+				}
+				done <- r
+			}()
+			r.err = fn()
+			r.panicked = false
+		})
+
+		select {
+		case r := <-done:
+			if r.panicked {
+				panic(r.value)
+			}
+			return r.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}, append([]Option{withCancel(ctx.Done())}, opts...)...)
+	return r.Stdout, r.Stderr, r.Err
+}
+
+// result is the outcome of a function run by OutputContext; if the
+// function panicked, value holds the recovered panic value.
+type result struct {
+	err      error
+	panicked bool
+	value    any
+}
+
+// OutputTimeout captures the stdout and stderr output produced during
+// execution of a supplied function, as for OutputContext, using a
+// context with the specified timeout.
+//
+// If the function does not return within the timeout, any output
+// captured up to that point is returned together with
+// context.DeadlineExceeded.  As with OutputContext, the function may
+// continue to run in the background.
+//
+// Any options are applied as for OutputContext.
+//
+// Example:
+//
+//	  func TestSomething(t *testing.T) {
+//		stdout, _, err := capture.OutputTimeout(2 * time.Second, func () error {
+//		   return doSomething()
+//		})
+//		if errors.Is(err, context.DeadlineExceeded) {
+//		   t.Fatalf("timed out; output: %v", stdout)
+//		}
+//	  }
+func OutputTimeout(d time.Duration, fn func() error, opts ...Option) ([]string, []string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	return OutputContext(ctx, fn, opts...)
+}
@@ -0,0 +1,262 @@
+package capture
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOutputContext(t *testing.T) {
+	t.Run("when function completes", func(t *testing.T) {
+		// ARRANGE
+		fnerr := errors.New("function error")
+
+		// ACT
+		stdout, stderr, err := OutputContext(context.Background(), func() error {
+			fmt.Println("to stdout")
+			os.Stderr.WriteString("to stderr")
+			return fnerr
+		})
+
+		// ASSERT
+		t.Run("returns error", func(t *testing.T) {
+			wanted := fnerr
+			got := err
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("output captured", func(t *testing.T) {
+			wanted := [][]string{{"to stdout"}, {"to stderr"}}
+			got := [][]string{stdout, stderr}
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+	})
+
+	t.Run("when function panics", func(t *testing.T) {
+		// ARRANGE
+		ogout, ogerr := os.Stdout, os.Stderr
+
+		// ACT
+		recovered := func() (r any) {
+			defer func() { r = recover() }()
+			_, _, _ = OutputContext(context.Background(), func() error {
+				fmt.Println("before panic")
+				panic("function panicked")
+			})
+			return nil
+		}()
+
+		// ASSERT
+		t.Run("panic is re-raised", func(t *testing.T) {
+			wanted := "function panicked"
+			got := recovered
+			if got != wanted {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("stdout and stderr restored", func(t *testing.T) {
+			wanted := []*os.File{ogout, ogerr}
+			got := []*os.File{os.Stdout, os.Stderr}
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+	})
+
+	t.Run("when context is cancelled", func(t *testing.T) {
+		// ARRANGE
+		ogout, ogerr := os.Stdout, os.Stderr
+		ctx, cancel := context.WithCancel(context.Background())
+		release := make(chan struct{})
+		defer close(release)
+
+		// ACT
+		stdout, stderr, err := OutputContext(ctx, func() error {
+			fmt.Println("before cancel")
+			cancel()
+			<-release
+			return nil
+		})
+
+		// ASSERT
+		t.Run("returns context error", func(t *testing.T) {
+			wanted := context.Canceled
+			got := err
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("partial output captured", func(t *testing.T) {
+			wanted := []string{"before cancel"}
+			got := stdout
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+			if stderr != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", stderr)
+			}
+		})
+
+		t.Run("streams restored", func(t *testing.T) {
+			if os.Stdout != ogout || os.Stderr != ogerr {
+				t.Errorf("os.Stdout and/or os.Stderr not restored")
+			}
+		})
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		// ACT
+		stdout, stderr, err := OutputContext(context.Background(), func() error {
+			fmt.Println("abcdefghij")
+			os.Stderr.WriteString("klmn")
+			return nil
+		}, WithMaxLineLength(3))
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		wanted := [][]string{{"abc", "def", "ghi", "j"}, {"klm", "n"}}
+		got := [][]string{stdout, stderr}
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when cancelled while reading stdin", func(t *testing.T) {
+		// ARRANGE
+		ogin := os.Stdin
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// input that is never supplied
+		pr, pw := io.Pipe()
+		defer pw.Close()
+
+		type result struct {
+			input string
+			err   error
+		}
+		read := make(chan result, 1)
+
+		// ACT
+		_, _, err := OutputContext(ctx, func() error {
+			in := bufio.NewReader(os.Stdin)
+			cancel()
+			s, err := in.ReadString('\n')
+			read <- result{s, err}
+			return err
+		}, WithStdinReader(pr))
+
+		// ASSERT
+		t.Run("returns context error", func(t *testing.T) {
+			wanted := context.Canceled
+			got := err
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("read unblocked", func(t *testing.T) {
+			select {
+			case r := <-read:
+				if r.input != "" || r.err != io.EOF {
+					t.Errorf("\nwanted: \"\", io.EOF\ngot   : %q, %v", r.input, r.err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Error("read from stdin not unblocked")
+			}
+		})
+
+		t.Run("stdin restored", func(t *testing.T) {
+			wanted := ogin
+			got := os.Stdin
+			if wanted != got {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+	})
+}
+
+func TestOutputTimeout(t *testing.T) {
+	t.Run("when function completes", func(t *testing.T) {
+		// ACT
+		stdout, _, err := OutputTimeout(time.Second, func() error {
+			fmt.Println("to stdout")
+			return nil
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", err)
+		}
+
+		wanted := []string{"to stdout"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		// ACT
+		stdout, _, err := OutputTimeout(time.Second, func() error {
+			fmt.Println("abcdefghij")
+			return nil
+		}, WithMaxLineLength(3))
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", err)
+		}
+
+		wanted := []string{"abc", "def", "ghi", "j"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when function times out", func(t *testing.T) {
+		// ARRANGE
+		release := make(chan struct{})
+		defer close(release)
+
+		// ACT
+		stdout, _, err := OutputTimeout(100*time.Millisecond, func() error {
+			fmt.Println("before timeout")
+			<-release
+			return nil
+		})
+
+		// ASSERT
+		t.Run("returns deadline exceeded", func(t *testing.T) {
+			wanted := context.DeadlineExceeded
+			got := err
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("partial output captured", func(t *testing.T) {
+			wanted := []string{"before timeout"}
+			got := stdout
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+	})
+}
@@ -0,0 +1,52 @@
+// Package cstdio provides capture of output written by C code (via
+// cgo) using C stdio.
+//
+// It is provided as a separate package so that modules importing
+// github.com/blugnu/capture are not required to build with cgo; only
+// those importing this package are.
+package cstdio
+
+import (
+	"fmt"
+
+	"github.com/blugnu/capture"
+)
+
+// Output captures the stdout and stderr output produced during
+// execution of a supplied function, as for capture.OutputFD, also
+// capturing any output written by C code (via cgo) using C stdio (e.g.
+// printf) that is buffered by the C library when the function returns.
+//
+// C stdio buffers output independently of Go; output written to stdout
+// is typically fully buffered when stdout is not a terminal (as is the
+// case while it is captured), so is not written to the file descriptor
+// until the C stdio buffer is flushed.  Output flushes all C stdio
+// streams (using fflush(NULL)) before redirecting the file descriptors,
+// so that output buffered beforehand is not captured, and again before
+// restoring them, so that output buffered by the function is captured.
+//
+// Output requires cgo.  If the package is built without cgo (e.g.
+// CGO_ENABLED=0), errors.ErrUnsupported is returned (wrapped with
+// capture.ErrStdoutCapture) and the function is not called.  The
+// limitations of capture.OutputFD also apply; in particular, on Windows
+// the C runtime file descriptors are not redirected, so C stdio output
+// is not captured.
+//
+// Options are applied as for capture.OutputFD.
+//
+// Example:
+//
+//	  func TestHello(t *testing.T) {
+//		stdout, _, err := cstdio.Output(func () error {
+//		   C.hello() // printf("hello\n")
+//		   return nil
+//		})
+//
+//		// stdout: [hello]
+//	  }
+func Output(fn func() error, opts ...capture.Option) ([]string, []string, error) {
+	if err := (stdio{}).Flush(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", capture.ErrStdoutCapture, err)
+	}
+	return capture.OutputFD(fn, append([]capture.Option{capture.WithFlush(stdio{})}, opts...)...)
+}
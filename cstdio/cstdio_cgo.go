@@ -0,0 +1,15 @@
+//go:build cgo
+
+package cstdio
+
+// #include <stdio.h>
+import "C"
+
+// stdio flushes all C stdio output streams.
+type stdio struct{}
+
+// Flush flushes all C stdio output streams.
+func (stdio) Flush() error {
+	C.fflush(nil)
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build !cgo
+
+package cstdio
+
+import "errors"
+
+// stdio flushes all C stdio output streams; it is not supported
+// without cgo.
+type stdio struct{}
+
+// Flush returns errors.ErrUnsupported.
+func (stdio) Flush() error {
+	return errors.ErrUnsupported
+}
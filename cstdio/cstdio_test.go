@@ -0,0 +1,48 @@
+//go:build cgo && (aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package cstdio
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/blugnu/capture/internal/cprint"
+)
+
+func TestOutput(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	stdout, stderr, err := Output(func() error {
+		cprint.Stdout("buffered by C stdio\n")
+		cprint.Stderr("to C stderr\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout captured", func(t *testing.T) {
+		wanted := []string{"buffered by C stdio"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stderr captured", func(t *testing.T) {
+		wanted := []string{"to C stderr"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package capture
+
+import "os"
+
+// SnapshotStdout returns the current os.Stdout, which is the captured
+// file if a capture of stdout is in progress.
+//
+// Output functions capture output written to os.Stdout while the
+// capture is in progress, including output written by any function that
+// refers to os.Stdout when called, such as fmt.Println, or
+// fmt.Fprintln(os.Stdout, ...) (where os.Stdout is evaluated when
+// fmt.Fprintln is called).  Output written to a file obtained from
+// os.Stdout before the capture was started (e.g. w := os.Stdout, or a
+// logger or bufio.Writer initialised with os.Stdout) is NOT captured;
+// it is written to the file as it was when obtained.
+//
+// Code that must hold a reference to os.Stdout, and that may be called
+// while a capture is in progress, should obtain the reference when it
+// is needed, rather than retaining it.  SnapshotStdout may be used
+// for this purpose by code running concurrently with a capture: unlike
+// reading os.Stdout directly, it is synchronized with the replacement
+// of os.Stdout when a capture is started or completed.
+func SnapshotStdout() *os.File {
+	return current(&os.Stdout)
+}
+
+// SnapshotStderr returns the current os.Stderr, which is the captured
+// file if a capture of stderr is in progress.  The considerations
+// described for SnapshotStdout apply equally to os.Stderr.
+func SnapshotStderr() *os.File {
+	return current(&os.Stderr)
+}
+
+// current returns the current value of a file that may be captured.
+func current(t **os.File) *os.File {
+	redirectionsMu.Lock()
+	defer redirectionsMu.Unlock()
+	return *t
+}
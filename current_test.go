@@ -0,0 +1,107 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSnapshotStdout(t *testing.T) {
+	// ARRANGE
+	og := os.Stdout
+	var during *os.File
+
+	// ACT
+	_, _, _ = Output(func() error {
+		during = SnapshotStdout()
+		return nil
+	})
+
+	// ASSERT
+	t.Run("during capture", func(t *testing.T) {
+		if during == og {
+			t.Error("returned original stdout")
+		}
+	})
+
+	t.Run("after capture", func(t *testing.T) {
+		wanted := og
+		got := SnapshotStdout()
+		if wanted != got {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+}
+
+func TestSnapshotStderr(t *testing.T) {
+	// ARRANGE
+	og := os.Stderr
+	var during *os.File
+
+	// ACT
+	_, _, _ = Output(func() error {
+		during = SnapshotStderr()
+		return nil
+	})
+
+	// ASSERT
+	if during == og {
+		t.Error("returned original stderr")
+	}
+	if wanted, got := og, SnapshotStderr(); wanted != got {
+		t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+	}
+}
+
+// TestCapturedWritePatterns establishes which patterns of writing to
+// os.Stdout are captured.
+func TestCapturedWritePatterns(t *testing.T) {
+	t.Run("evaluated during capture", func(t *testing.T) {
+		// ACT
+		stdout, _, _ := Output(func() error {
+			fmt.Println("Println")
+			fmt.Fprintln(os.Stdout, "Fprintln(os.Stdout)")
+			fmt.Fprintf(os.Stdout, "Fprintf(os.Stdout)\n")
+			os.Stdout.WriteString("os.Stdout.WriteString\n")
+			fmt.Fprintln(SnapshotStdout(), "Fprintln(SnapshotStdout())")
+			return nil
+		})
+
+		// ASSERT
+		wanted := []string{
+			"Println",
+			"Fprintln(os.Stdout)",
+			"Fprintf(os.Stdout)",
+			"os.Stdout.WriteString",
+			"Fprintln(SnapshotStdout())",
+		}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("obtained before capture", func(t *testing.T) {
+		// ARRANGE
+		var inner []string
+
+		// ACT
+		outer, _, _ := Output(func() error {
+			w := os.Stdout // the outer capture
+			inner, _, _ = Output(func() error {
+				fmt.Fprintln(w, "bypasses inner capture")
+				return nil
+			})
+			return nil
+		})
+
+		// ASSERT
+		if inner != nil {
+			t.Errorf("inner:\nwanted: nil\ngot   : %q", inner)
+		}
+		if wanted, got := []string{"bypasses inner capture"}, outer; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("outer:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
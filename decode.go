@@ -0,0 +1,65 @@
+package capture
+
+import "fmt"
+
+// Decoder converts output in some encoding to UTF-8.
+//
+// Decoder is satisfied by *encoding.Decoder from the
+// golang.org/x/text/encoding packages (e.g. charmap.Windows1252 or
+// japanese.ShiftJIS), without this package depending on them.
+type Decoder interface {
+	Bytes(b []byte) ([]byte, error)
+}
+
+// OutputDecode captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, converting the
+// captured output to UTF-8 using a supplied decoder before splitting
+// into lines.
+//
+// This allows assertions to be made on output from code that emits
+// output in a legacy (non-UTF-8) encoding.  The output of each stream
+// is decoded once it has been captured in full, so multi-byte
+// characters written (or read from the pipe) in separate chunks are
+// decoded correctly.
+//
+// If the output of a stream cannot be decoded, the captured output is
+// discarded and ErrStdoutCapture or ErrStderrCapture is returned,
+// wrapping the decoder error.  Error handling is otherwise identical
+// to Output.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, _, err := capture.OutputDecode(charmap.Windows1252.NewDecoder(), func () error {
+//		   return legacyReport()
+//		})
+//
+//		fmt.Printf("stdout: %v", stdout)
+//		fmt.Printf("error: %v", err)
+//	  }
+func OutputDecode(dec Decoder, fn func() error) ([]string, []string, error) {
+	stdout, stderr, err := output(fn)
+
+	o := outcome{err: err}
+	if stdout, err = decode(dec, stdout); err != nil {
+		o.stdout = fmt.Errorf("%w: %w", ErrStdoutCapture, err)
+	}
+	if stderr, err = decode(dec, stderr); err != nil {
+		o.stderr = fmt.Errorf("%w: %w", ErrStderrCapture, err)
+	}
+
+	return lines(string(stdout)), lines(string(stderr)), o.join()
+}
+
+// decode decodes captured output.  If there is no output, or if the
+// output cannot be decoded, nil is returned.
+func decode(dec Decoder, b []byte) ([]byte, error) {
+	if b == nil {
+		return nil, nil
+	}
+	b, err := dec.Bytes(b)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,85 @@
+package capture
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// utf16Decoder is a Decoder for UTF-16LE encoded output.
+type utf16Decoder struct{}
+
+var errOddLength = errors.New("odd length")
+
+func (utf16Decoder) Bytes(b []byte) ([]byte, error) {
+	if len(b)%2 != 0 {
+		return nil, errOddLength
+	}
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i < len(b); i += 2 {
+		u = append(u, uint16(b[i])|uint16(b[i+1])<<8)
+	}
+	var result []byte
+	for _, r := range utf16.Decode(u) {
+		result = utf8.AppendRune(result, r)
+	}
+	return result, nil
+}
+
+func TestOutputDecode(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	stdout, stderr, err := OutputDecode(utf16Decoder{}, func() error {
+		// "é\n" in UTF-16LE, with the first character split across writes
+		os.Stdout.Write([]byte{0xe9})
+		os.Stdout.Write([]byte{0x00, '\n', 0x00})
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		wanted := []string{"é"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		got := stderr
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %q", got)
+		}
+	})
+
+	t.Run("when output cannot be decoded", func(t *testing.T) {
+		// ACT
+		_, stderr, err := OutputDecode(utf16Decoder{}, func() error {
+			os.Stderr.Write([]byte{'x'})
+			return nil
+		})
+
+		// ASSERT
+		for _, wanted := range []error{ErrStderrCapture, errOddLength} {
+			if !errors.Is(err, wanted) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, err)
+			}
+		}
+		if stderr != nil {
+			t.Errorf("\nwanted: nil\ngot   : %q", stderr)
+		}
+	})
+}
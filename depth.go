@@ -0,0 +1,47 @@
+package capture
+
+import "os"
+
+// Depth returns the number of captures of os.Stdout and/or os.Stderr
+// currently in progress.  Nested captures (e.g. a call to Output from
+// a function being captured by Output) each add one to the depth.
+//
+// Depth is determined from the redirections of os.Stdout and os.Stderr
+// that are currently installed; captures that do not redirect these
+// variables (File and Writer captures of other targets, or OutputFD,
+// which redirects file descriptors) are not counted.
+//
+// Note that captures are not confined to the calling goroutine; a
+// capture in progress on any goroutine is reported.
+func Depth() int {
+	redirectionsMu.Lock()
+	defer redirectionsMu.Unlock()
+
+	return max(len(redirections[&os.Stdout]), len(redirections[&os.Stderr]))
+}
+
+// IsCapturing returns true if a capture of os.Stdout and/or os.Stderr is
+// currently in progress (i.e. Depth() > 0).  This allows a test
+// framework or helper to avoid capturing output that is already being
+// captured, or to warn about it.  It also allows code that behaves
+// differently when writing to a terminal (e.g. using color or animating
+// progress) to choose its plain output deterministically while its
+// output is captured, including by OutputContext (whose function runs
+// on a different goroutine), rather than relying on detecting that
+// os.Stdout is not a terminal.
+//
+// Example:
+//
+//	  func runQuietly(fn func() error) error {
+//		if capture.IsCapturing() {
+//		   return fn() // output is already captured by the caller
+//		}
+//		return capture.Suppress(fn)
+//	  }
+//
+//	  func useColor() bool {
+//		return !capture.IsCapturing() && isatty(os.Stdout)
+//	  }
+func IsCapturing() bool {
+	return Depth() > 0
+}
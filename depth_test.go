@@ -0,0 +1,66 @@
+package capture
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDepth(t *testing.T) {
+	// ARRANGE
+	var depths []int
+	var capturing []bool
+	record := func() {
+		depths = append(depths, Depth())
+		capturing = append(capturing, IsCapturing())
+	}
+
+	// ACT
+	record()
+	_, _, _ = Output(func() error {
+		record()
+		_, _ = Stdout(func() error { record(); return nil })
+		_, _ = Stderr(func() error { record(); return nil })
+		_, _, _ = Output(func() error { record(); return nil })
+		record()
+		return nil
+	})
+	_, _ = Combined(func() error { record(); return nil })
+	record()
+
+	// ASSERT
+	t.Run("Depth", func(t *testing.T) {
+		wanted := []int{0, 1, 2, 2, 2, 1, 1, 0}
+		got := depths
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("IsCapturing", func(t *testing.T) {
+		wanted := []bool{false, true, true, true, true, true, true, false}
+		got := capturing
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+}
+
+func TestIsCapturingOutputContext(t *testing.T) {
+	// ARRANGE
+	var captured []bool
+	record := func() { captured = append(captured, IsCapturing()) }
+
+	// ACT
+	record()
+	_, _, _ = Output(func() error { record(); return nil })
+	_, _, _ = OutputContext(context.Background(), func() error { record(); return nil })
+	record()
+
+	// ASSERT
+	wanted := []bool{false, true, true, false}
+	got := captured
+	if !reflect.DeepEqual(wanted, got) {
+		t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+	}
+}
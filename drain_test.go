@@ -0,0 +1,70 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithDrainTimeout(t *testing.T) {
+	t.Run("when output is drained", func(t *testing.T) {
+		// ACT
+		stdout, _, err := Output(func() error {
+			fmt.Println("to stdout")
+			return nil
+		}, WithDrainTimeout(time.Second))
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if wanted, got := []string{"to stdout"}, stdout; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when copy does not complete", func(t *testing.T) {
+		// ARRANGE
+		release := make(chan struct{})
+		wg := &sync.WaitGroup{}
+		wg.Add(2)
+		cpy := func(dst io.Writer, src io.Reader) (int64, error) {
+			defer wg.Done()
+			n, err := io.Copy(dst, src)
+			<-release
+			_, _ = dst.Write([]byte("after timeout\n"))
+			return n, err
+		}
+
+		// ACT
+		stdout, stderr, err := Output(func() error {
+			fmt.Println("before timeout")
+			os.Stderr.WriteString("to stderr\n")
+			return nil
+		}, WithCopyFunc(cpy), WithDrainTimeout(10*time.Millisecond))
+		close(release)
+		wg.Wait() // the abandoned copies complete without panicking
+
+		// ASSERT
+		t.Run("returns error", func(t *testing.T) {
+			for _, wanted := range []error{ErrDrainTimeout, ErrStdoutCapture, ErrStderrCapture} {
+				if got := err; !errors.Is(got, wanted) {
+					t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+				}
+			}
+		})
+
+		t.Run("returns partial output", func(t *testing.T) {
+			wanted := [][]string{{"before timeout"}, {"to stderr"}}
+			got := [][]string{stdout, stderr}
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	})
+}
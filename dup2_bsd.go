@@ -0,0 +1,10 @@
+//go:build aix || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package capture
+
+import "syscall"
+
+// dup2 duplicates oldfd onto newfd.
+func dup2(oldfd, newfd int) error {
+	return syscall.Dup2(oldfd, newfd)
+}
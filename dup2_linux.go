@@ -0,0 +1,9 @@
+package capture
+
+import "syscall"
+
+// dup2 duplicates oldfd onto newfd.  syscall.Dup2 is not available on
+// all linux architectures, so dup3 is used.
+func dup2(oldfd, newfd int) error {
+	return syscall.Dup3(oldfd, newfd, 0)
+}
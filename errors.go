@@ -3,6 +3,25 @@ package capture
 import "errors"
 
 var (
-	ErrStderrCapture = errors.New("stderr capture error")
-	ErrStdoutCapture = errors.New("stdout capture error")
+	ErrAlreadyStarted = errors.New("capture already started")
+	ErrCapture        = errors.New("capture error")
+	ErrCopyStopped    = errors.New("capture copy stopped")
+	ErrDrainTimeout   = errors.New("capture drain timeout")
+	ErrFileCapture    = &captureError{"file capture error"}
+	ErrInvalidJSON    = errors.New("invalid JSON")
+	ErrNotStarted     = errors.New("capture not started")
+	ErrPipeCreate     = errors.New("pipe create error")
+	ErrStderrCapture  = &captureError{"stderr capture error"}
+	ErrStdoutCapture  = &captureError{"stdout capture error"}
+	ErrTruncated      = errors.New("captured output truncated")
 )
+
+// captureError is the type of the stream-specific capture sentinels,
+// each of which wraps ErrCapture so that errors.Is(err, ErrCapture) is
+// true for a failure to capture any stream.
+type captureError struct {
+	msg string
+}
+
+func (e *captureError) Error() string { return e.msg }
+func (e *captureError) Unwrap() error { return ErrCapture }
@@ -0,0 +1,63 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestErrCapture(t *testing.T) {
+	// ARRANGE
+	testcases := []struct {
+		name     string
+		sentinel error
+	}{
+		{name: "stdout", sentinel: ErrStdoutCapture},
+		{name: "stderr", sentinel: ErrStderrCapture},
+		{name: "file", sentinel: ErrFileCapture},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ACT
+			err := fmt.Errorf("%w: %w", tc.sentinel, errors.New("copy error"))
+
+			// ASSERT
+			t.Run("is ErrCapture", func(t *testing.T) {
+				if !errors.Is(err, ErrCapture) {
+					t.Errorf("\nwanted: %v\ngot   : %v", ErrCapture, err)
+				}
+			})
+
+			t.Run("is sentinel", func(t *testing.T) {
+				if !errors.Is(err, tc.sentinel) {
+					t.Errorf("\nwanted: %v\ngot   : %v", tc.sentinel, err)
+				}
+			})
+		})
+	}
+
+	t.Run("sentinels are distinct", func(t *testing.T) {
+		if errors.Is(ErrStdoutCapture, ErrStderrCapture) || errors.Is(ErrStderrCapture, ErrStdoutCapture) {
+			t.Error("ErrStdoutCapture and ErrStderrCapture match each other")
+		}
+	})
+
+	t.Run("when copying fails", func(t *testing.T) {
+		// ARRANGE
+		cpy := func(dst io.Writer, src io.Reader) (int64, error) {
+			_, _ = io.Copy(dst, src)
+			return 0, errors.New("copy error")
+		}
+
+		// ACT
+		_, _, err := Output(func() error { return nil }, WithCopyFunc(cpy))
+
+		// ASSERT
+		for _, wanted := range []error{ErrCapture, ErrStdoutCapture, ErrStderrCapture} {
+			if !errors.Is(err, wanted) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package capture
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// exitFn is the function called by Exit, other than when called by a
+// function whose output is being captured by OutputExit.
+var exitFn = os.Exit
+
+// exiting holds the id of the goroutine on which a function whose
+// output is being captured by OutputExit is running, or 0 if there is
+// none.  Only a call of Exit from that goroutine is intercepted.
+var exiting atomic.Uint64
+
+// exit is the value with which Exit panics when called by a function
+// whose output is being captured by OutputExit.
+type exit int
+
+// Exit causes the current program to exit with the given status code,
+// exactly as os.Exit, except when called by a function whose output is
+// being captured by OutputExit, in which case the status code is
+// recorded and the function is abandoned, without terminating the
+// program.
+//
+// Code that is to be tested using OutputExit must call Exit (rather
+// than os.Exit), typically by arranging for the exit function used by
+// the code to be replaceable.
+//
+// Example:
+//
+//	  var exit = capture.Exit
+//
+//	  func main() {
+//		if err := run(); err != nil {
+//		   fmt.Fprintln(os.Stderr, err)
+//		   exit(1)
+//		}
+//	  }
+func Exit(code int) {
+	if id := exiting.Load(); id != 0 && id == goid() {
+		panic(exit(code))
+	}
+	exitFn(code)
+}
+
+// OutputExit captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, also returning the
+// status code with which the function exited by calling Exit.  If the
+// function returns without calling Exit, the returned code is 0.
+//
+// When the function calls Exit, the function is abandoned (by means of
+// a panic, which is recovered by OutputExit) and the output captured up
+// to that point is returned, together with the status code.  Unlike
+// os.Exit, any functions deferred by the function are run.
+//
+// The function must call Exit from the goroutine on which it was
+// called; a call of os.Exit, or of Exit from any other goroutine (e.g.
+// one started by the function), is not intercepted and terminates the
+// program with the status code, exactly as os.Exit.
+//
+// Example:
+//
+//	  func TestMain_WhenRunFails(t *testing.T) {
+//		_, stderr, code, err := capture.OutputExit(func () error {
+//		   main()
+//		   return nil
+//		})
+//
+//		// code: 1
+//		...
+//	  }
+func OutputExit(fn func() error) ([]string, []string, int, error) {
+	mu.lock()
+	defer mu.unlock()
+
+	og := exiting.Swap(goid())
+	defer exiting.Store(og)
+
+	var code int
+	stdout, stderr, err := Output(func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				c, ok := r.(exit)
+				if !ok {
+					panic(r)
+				}
+				code = int(c)
+			}
+		}()
+		return fn()
+	})
+
+	return stdout, stderr, code, err
+}
@@ -0,0 +1,169 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestExit(t *testing.T) {
+	// ARRANGE
+	og := exitFn
+	defer func() { exitFn = og }()
+	var code int
+	exitFn = func(c int) { code = c }
+
+	// ACT
+	Exit(2)
+
+	// ASSERT
+	wanted := 2
+	got := code
+	if wanted != got {
+		t.Errorf("\nwanted: %d\ngot   : %d", wanted, got)
+	}
+}
+
+func TestOutputExit(t *testing.T) {
+	t.Run("when function exits", func(t *testing.T) {
+		// ARRANGE
+		var deferred, abandoned bool
+
+		// ACT
+		stdout, stderr, code, err := OutputExit(func() error {
+			defer func() { deferred = true }()
+			fmt.Println("to stdout")
+			os.Stderr.WriteString("to stderr\n")
+			Exit(3)
+			abandoned = true
+			return errors.New("not returned")
+		})
+
+		// ASSERT
+		t.Run("code", func(t *testing.T) {
+			wanted := 3
+			got := code
+			if wanted != got {
+				t.Errorf("\nwanted: %d\ngot   : %d", wanted, got)
+			}
+		})
+
+		t.Run("error", func(t *testing.T) {
+			if err != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", err)
+			}
+		})
+
+		t.Run("stdout", func(t *testing.T) {
+			wanted := []string{"to stdout"}
+			got := stdout
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+
+		t.Run("stderr", func(t *testing.T) {
+			wanted := []string{"to stderr"}
+			got := stderr
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+
+		t.Run("function abandoned", func(t *testing.T) {
+			if abandoned {
+				t.Error("function continued after Exit")
+			}
+			if !deferred {
+				t.Error("deferred function not run")
+			}
+		})
+	})
+
+	t.Run("when exit is called from another goroutine", func(t *testing.T) {
+		// ARRANGE
+		og := exitFn
+		defer func() { exitFn = og }()
+		exited := make(chan int, 1)
+		exitFn = func(c int) { exited <- c }
+
+		// ACT
+		_, _, code, err := OutputExit(func() error {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				Exit(4)
+			}()
+			<-done
+			return nil
+		})
+
+		// ASSERT
+		if wanted, got := 0, code; wanted != got || err != nil {
+			t.Errorf("code:\nwanted: %d\ngot   : %d (error: %v)", wanted, got, err)
+		}
+		if wanted, got := 4, <-exited; wanted != got {
+			t.Errorf("exit code:\nwanted: %d\ngot   : %d", wanted, got)
+		}
+	})
+
+	t.Run("when function returns", func(t *testing.T) {
+		// ARRANGE
+		fnerr := errors.New("function error")
+
+		// ACT
+		stdout, _, code, err := OutputExit(func() error {
+			fmt.Println("to stdout")
+			return fnerr
+		})
+
+		// ASSERT
+		if wanted, got := 0, code; wanted != got {
+			t.Errorf("code:\nwanted: %d\ngot   : %d", wanted, got)
+		}
+		if wanted, got := fnerr, err; !errors.Is(got, wanted) {
+			t.Errorf("error:\nwanted: %v\ngot   : %v", wanted, got)
+		}
+		if wanted, got := []string{"to stdout"}, stdout; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("stdout:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when function panics", func(t *testing.T) {
+		// ARRANGE
+		og := os.Stdout
+		defer func() {
+			r := recover()
+			if wanted, got := "function panic", r; wanted != got {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+			if os.Stdout != og {
+				t.Error("stdout not restored")
+			}
+		}()
+
+		// ACT
+		_, _, _, _ = OutputExit(func() error { panic("function panic") })
+	})
+
+	t.Run("restores exit function", func(t *testing.T) {
+		// ARRANGE
+		og := exitFn
+		defer func() { exitFn = og }()
+		var code int
+		exitFn = func(c int) { code = c }
+
+		// ACT
+		_, _, _, _ = OutputExit(func() error { return nil })
+		Exit(4)
+
+		// ASSERT
+		wanted := 4
+		got := code
+		if wanted != got {
+			t.Errorf("\nwanted: %d\ngot   : %d", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package capture
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Expectation accumulates expectations of the output captured from a
+// function, which are evaluated when Run is called.
+//
+// An Expectation is obtained using Expect.
+type Expectation struct {
+	fn     func() error
+	checks []func(t testing.TB, r Result)
+}
+
+// Expect returns an Expectation for the output of a supplied function.
+// Expectations are added using the methods of the returned value, which
+// may be chained; the function is not called until Run is called.
+//
+// Example:
+//
+//	  func TestSomething(t *testing.T) {
+//		capture.Expect(func () error {
+//		   return doSomething()
+//		}).
+//		   StdoutContains("started").
+//		   StderrEquals(nil).
+//		   NoError().
+//		   Run(t)
+//	  }
+func Expect(fn func() error) *Expectation {
+	return &Expectation{fn: fn}
+}
+
+// expect adds a check to the expectation.
+func (e *Expectation) expect(check func(t testing.TB, r Result)) *Expectation {
+	e.checks = append(e.checks, check)
+	return e
+}
+
+// StdoutContains adds an expectation that the captured stdout output
+// contains a substring (which may span lines).
+func (e *Expectation) StdoutContains(s string) *Expectation {
+	return e.expect(func(t testing.TB, r Result) {
+		t.Helper()
+		if !strings.Contains(r.StdoutString(), s) {
+			t.Errorf("stdout: does not contain %q:\n  got   : %q", s, r.Stdout)
+		}
+	})
+}
+
+// StderrContains adds an expectation that the captured stderr output
+// contains a substring (which may span lines).
+func (e *Expectation) StderrContains(s string) *Expectation {
+	return e.expect(func(t testing.TB, r Result) {
+		t.Helper()
+		if !strings.Contains(r.StderrString(), s) {
+			t.Errorf("stderr: does not contain %q:\n  got   : %q", s, r.Stderr)
+		}
+	})
+}
+
+// StdoutEquals adds an expectation that the captured stdout lines are
+// equal to the wanted lines.  Differences are reported line-by-line.
+func (e *Expectation) StdoutEquals(want []string) *Expectation {
+	return e.expect(func(t testing.TB, r Result) {
+		t.Helper()
+		if diff := diffLines(want, r.Stdout); diff != "" {
+			t.Errorf("stdout:\n%s", diff)
+		}
+	})
+}
+
+// StderrEquals adds an expectation that the captured stderr lines are
+// equal to the wanted lines.  Differences are reported line-by-line.
+func (e *Expectation) StderrEquals(want []string) *Expectation {
+	return e.expect(func(t testing.TB, r Result) {
+		t.Helper()
+		if diff := diffLines(want, r.Stderr); diff != "" {
+			t.Errorf("stderr:\n%s", diff)
+		}
+	})
+}
+
+// NoError adds an expectation that no error is returned.
+func (e *Expectation) NoError() *Expectation {
+	return e.expect(func(t testing.TB, r Result) {
+		t.Helper()
+		if r.Err != nil {
+			t.Errorf("error:\n  wanted: <nil>\n  got   : %v", r.Err)
+		}
+	})
+}
+
+// Error adds an expectation that the error returned satisfies
+// errors.Is(err, want).
+func (e *Expectation) Error(want error) *Expectation {
+	return e.expect(func(t testing.TB, r Result) {
+		t.Helper()
+		if !errors.Is(r.Err, want) {
+			t.Errorf("error:\n  wanted: %v\n  got   : %v", want, r.Err)
+		}
+	})
+}
+
+// Run captures the output of the function (as for OutputResult) and
+// evaluates all expectations, failing the test (using t.Errorf) for
+// every expectation that is not met.
+func (e *Expectation) Run(t testing.TB) {
+	t.Helper()
+	r := OutputResult(e.fn)
+	for _, check := range e.checks {
+		check(t, r)
+	}
+}
@@ -0,0 +1,79 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExpect(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	fn := func() error {
+		fmt.Println("started")
+		fmt.Println("finished")
+		os.Stderr.WriteString("warning\n")
+		return fnerr
+	}
+
+	t.Run("when all expectations are met", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+
+		// ACT
+		Expect(fn).
+			StdoutContains("started\nfinished").
+			StdoutEquals([]string{"started", "finished"}).
+			StderrContains("warn").
+			StderrEquals([]string{"warning"}).
+			Error(fnerr).
+			Run(mock)
+
+		// ASSERT
+		if len(mock.errors) != 0 {
+			t.Errorf("\nwanted: no errors\ngot   : %v", mock.errors)
+		}
+	})
+
+	t.Run("when expectations are not met", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+
+		// ACT
+		Expect(fn).
+			StdoutContains("missing").
+			StderrEquals(nil).
+			NoError().
+			Run(mock)
+
+		// ASSERT
+		wanted := "stdout: does not contain \"missing\":\n  got   : [\"started\" \"finished\"]\n" +
+			"stderr:\nline 1:\n  wanted: <none>\n  got   : \"warning\"\n\n" +
+			"error:\n  wanted: <nil>\n  got   : function error"
+		got := strings.Join(mock.errors, "\n")
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("function is called by Run", func(t *testing.T) {
+		// ARRANGE
+		called := false
+		e := Expect(func() error { called = true; return nil }).NoError()
+
+		// ASSERT
+		if called {
+			t.Fatal("function called before Run")
+		}
+
+		// ACT
+		e.Run(t)
+
+		// ASSERT
+		if !called {
+			t.Error("function not called by Run")
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package capture
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// OutputFD captures the stdout and stderr output produced during
+// execution of a supplied function by redirecting the process file
+// descriptors 1 and 2, rather than the os.Stdout and os.Stderr
+// variables.
+//
+// This captures output that bypasses os.Stdout and os.Stderr, such as
+// output written directly to the file descriptors using syscalls, by C
+// code (via cgo), by the runtime (e.g. the print and println builtins)
+// or by child processes that inherit the descriptors.
+// Output written using os.Stdout and os.Stderr is also captured, unless
+// those variables have been replaced (e.g. by an enclosing call to
+// Output).
+//
+// OutputFD is platform-specific; it is supported on Linux, macOS, the
+// BSDs and Windows.  On other platforms, errors.ErrUnsupported is
+// returned (wrapped with ErrStdoutCapture) and the function is not
+// called.
+//
+// On Windows, the process standard output and error handles are
+// redirected (using SetStdHandle), capturing output written using the
+// handles obtained from GetStdHandle during the call.  os.Stdout and
+// os.Stderr hold the handles obtained when the process started, so
+// output written using them is not captured; nor is output written by
+// C runtime file descriptors initialised before the call.
+//
+// If WithFlush is specified, the flushers are flushed before the
+// descriptors are redirected, so that output they buffered beforehand
+// is not captured, and again after the function returns, so that output
+// they buffered during the call is captured; errors returned when
+// flushing before the call are ignored.
+//
+// Of the available options, only WithCopyFunc, WithReadChunkSize and
+// WithFlush apply to OutputFD; any other options are ignored.
+//
+// Error handling is otherwise identical to Output.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, stderr, err := capture.OutputFD(func () error {
+//		   _, err := syscall.Write(1, []byte("written to fd 1\n"))
+//		   return err
+//		})
+//
+//		fmt.Printf("stdout: %v", stdout) // [written to fd 1]
+//		fmt.Printf("stderr: %v", stderr)
+//		fmt.Printf("error: %v", err)
+//	  }
+func OutputFD(fn func() error, opts ...Option) ([]string, []string, error) {
+	cfg := newConfig(opts)
+	return outputFD(fn, cfg.flushers, cfg.copier())
+}
+
+// outputFD captures output as for OutputFD, copying output using the
+// supplied copy function and flushing the supplied flushers before the
+// descriptors are redirected and again before they are restored.
+func outputFD(fn func() error, flushers []interface{ Flush() error }, cp copyFunc) ([]string, []string, error) {
+	mu.lock()
+	defer mu.unlock()
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	for _, f := range flushers {
+		_ = f.Flush()
+	}
+
+	h := startHook()
+
+	releaseout, err := captureFD(1, h.tee(StdoutStream, stdout), cp)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrStdoutCapture, err)
+		h.stop(err)
+		return nil, nil, err
+	}
+	defer func() { _ = releaseout() }()
+
+	releaseerr, err := captureFD(2, h.tee(StderrStream, stderr), cp)
+	if err != nil {
+		_ = releaseout()
+		err = fmt.Errorf("%w: %w", ErrStderrCapture, err)
+		h.stop(err)
+		return nil, nil, err
+	}
+	defer func() { _ = releaseerr() }()
+
+	o := outcome{err: flush(flushers, fn)()}
+
+	if err := releaseout(); err != nil {
+		o.stdout = fmt.Errorf("%w: %w", ErrStdoutCapture, err)
+	}
+	if err := releaseerr(); err != nil {
+		o.stderr = fmt.Errorf("%w: %w", ErrStderrCapture, err)
+	}
+	h.stop(o.join())
+
+	return lines(string(captured(stdout, o.stdout))),
+		lines(string(captured(stderr, o.stderr))),
+		o.join()
+}
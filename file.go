@@ -0,0 +1,49 @@
+package capture
+
+import "os"
+
+// File captures the output written to an arbitrary *os.File variable
+// during execution of a supplied function.
+//
+// The target is the address of the variable to be redirected, which
+// may be os.Stdout or os.Stderr, a file opened by the caller, or an
+// exported *os.File variable of some other package.  The variable is
+// redirected to a pipe for the duration of the call and restored when
+// the function returns.
+//
+// Only writes made through the variable are captured; writes made
+// using a copy of the *os.File obtained before calling File are not.
+//
+// If an error occurs while capturing the output, the captured output is
+// discarded and ErrFileCapture is returned (ErrStdoutCapture or
+// ErrStderrCapture if the target is &os.Stdout or &os.Stderr), wrapped
+// with any error returned from the supplied function.
+//
+// Of the available options, only WithCopyFunc and WithReadChunkSize
+// apply to File; any other options are ignored.
+//
+// Example:
+//
+//	  var Log = os.Stderr // some package file variable
+//
+//	  func DoSomething() {
+//		output, err := capture.File(&Log, func () error {
+//		   fmt.Fprintln(Log, "some output")
+//		   return nil
+//		})
+//
+//		fmt.Printf("output: %v", output) // [some output]
+//		fmt.Printf("error: %v", err)
+//	  }
+func File(target **os.File, fn func() error, opts ...Option) ([]string, error) {
+	sentinel := ErrFileCapture
+	switch target {
+	case &os.Stdout:
+		sentinel = ErrStdoutCapture
+	case &os.Stderr:
+		sentinel = ErrStderrCapture
+	}
+
+	s, err := captureFile(target, sentinel, newConfig(opts).copier(), fn)
+	return lines(string(s)), err
+}
@@ -0,0 +1,85 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestFile(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	f, err := os.CreateTemp(t.TempDir(), "capture")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	target := f
+
+	// ACT
+	output, err := File(&target, func() error {
+		fmt.Fprintln(target, "to file (1)")
+		fmt.Fprintln(target, "to file (2)")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("output captured", func(t *testing.T) {
+		wanted := []string{"to file (1)", "to file (2)"}
+		got := output
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("file restored", func(t *testing.T) {
+		wanted := f
+		got := target
+		if wanted != got {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("when error copying captured buffer", func(t *testing.T) {
+		// ARRANGE
+		cpyerr := fmt.Errorf("copy error")
+		cp := func(dst io.Writer, src io.Reader) (int64, error) { _, _ = io.Copy(dst, src); return 0, cpyerr }
+
+		testcases := []struct {
+			name   string
+			target **os.File
+			error
+		}{
+			{name: "file", target: &target, error: ErrFileCapture},
+			{name: "stdout", target: &os.Stdout, error: ErrStdoutCapture},
+			{name: "stderr", target: &os.Stderr, error: ErrStderrCapture},
+		}
+		for _, tc := range testcases {
+			t.Run(tc.name, func(t *testing.T) {
+				// ACT
+				output, err := File(tc.target, func() error { fmt.Fprintln(*tc.target, "some output"); return nil }, WithCopyFunc(cp))
+
+				// ASSERT
+				wanted := tc.error
+				got := err
+				if !errors.Is(got, wanted) {
+					t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+				}
+				if output != nil {
+					t.Errorf("\nwanted: nil\ngot   : %v", output)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package capture
+
+// filterLines returns the lines of a stream for which a predicate
+// returns true.  If no lines remain, nil is returned.
+func filterLines(st Stream, l []string, predicate func(Stream, string) bool) []string {
+	var result []string
+	for _, s := range l {
+		if predicate(st, s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// OutputFilter captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, retaining only those
+// lines for which a supplied predicate returns true.
+//
+// The predicate is called with each captured line together with the
+// stream on which it was captured, so lines may be selected by stream
+// and content together.  If no lines remain for a stream, the result
+// for that stream is nil.
+//
+// Error handling is identical to Output.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, stderr, _ := capture.OutputFilter(
+//		   func(st capture.Stream, s string) bool { return strings.HasPrefix(s, "[mylib] ") },
+//		   func () error {
+//		      return doSomething()
+//		   })
+//
+//		fmt.Printf("stdout: %v", stdout) // only lines prefixed "[mylib] "
+//		fmt.Printf("stderr: %v", stderr)
+//	  }
+func OutputFilter(predicate func(stream Stream, line string) bool, fn func() error) ([]string, []string, error) {
+	stdout, stderr, err := Output(fn)
+	return filterLines(StdoutStream, stdout, predicate), filterLines(StderrStream, stderr, predicate), err
+}
@@ -0,0 +1,52 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOutputFilter(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	stdout, stderr, err := OutputFilter(
+		func(st Stream, s string) bool {
+			return st == StdoutStream && strings.HasPrefix(s, "[mylib] ")
+		},
+		func() error {
+			fmt.Println("[mylib] line 1")
+			fmt.Println("other")
+			fmt.Println("[mylib] line 2")
+			os.Stderr.WriteString("[mylib] on stderr\n")
+			return fnerr
+		})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		wanted := []string{"[mylib] line 1", "[mylib] line 2"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		got := stderr
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+}
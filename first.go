@@ -0,0 +1,67 @@
+package capture
+
+// OutputFirst captures the first n lines of stdout and stderr output
+// produced during execution of a supplied function, signalling the
+// function to stop once n lines have been captured.
+//
+// The function is passed a stop channel, which is closed once n lines
+// (of both streams combined) have been captured.  The function should
+// return promptly once the channel is closed; this requires cooperation
+// from the function but provides a clean pattern for bounded capture
+// of output from a producer that would otherwise run indefinitely
+// (such as a tail or watch loop).
+//
+// Any output after the first n lines (i.e. produced before the function
+// returns) is discarded.  If n is < 1, the channel is closed before the
+// function is called and no output is captured.
+//
+// Error handling is identical to OutputStream.
+//
+// Example:
+//
+//	  func TestWatch(t *testing.T) {
+//		stdout, _, err := capture.OutputFirst(3, func (stop <-chan struct{}) error {
+//		   for {
+//		      select {
+//		      case <-stop:
+//		         return nil
+//		      case <-time.After(10 * time.Millisecond):
+//		         fmt.Println("tick")
+//		      }
+//		   }
+//		})
+//
+//		fmt.Printf("stdout: %v", stdout) // [tick tick tick]
+//		fmt.Printf("error: %v", err)
+//	  }
+func OutputFirst(n int, fn func(stop <-chan struct{}) error) ([]string, []string, error) {
+	stop := make(chan struct{})
+	if n < 1 {
+		close(stop)
+	}
+
+	var (
+		stdout []string
+		stderr []string
+		count  int
+	)
+	err := OutputStream(func(st Stream, line string) {
+		if count >= n {
+			return
+		}
+
+		count++
+		switch st {
+		case StdoutStream:
+			stdout = append(stdout, line)
+		case StderrStream:
+			stderr = append(stderr, line)
+		}
+
+		if count == n {
+			close(stop)
+		}
+	}, func() error { return fn(stop) })
+
+	return stdout, stderr, err
+}
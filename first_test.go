@@ -0,0 +1,70 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputFirst(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	stdout, stderr, err := OutputFirst(3, func(stop <-chan struct{}) error {
+		for i := 1; ; i++ {
+			select {
+			case <-stop:
+				fmt.Println("after stop")
+				return fnerr
+			default:
+			}
+			if i%2 == 0 {
+				fmt.Fprintf(os.Stderr, "line %d\n", i)
+				continue
+			}
+			fmt.Printf("line %d\n", i)
+		}
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("lines captured", func(t *testing.T) {
+		got := len(stdout) + len(stderr)
+		if got != 3 {
+			t.Errorf("\nwanted: 3 lines\ngot   : %v %v", stdout, stderr)
+		}
+		for _, s := range stdout {
+			if s == "after stop" {
+				t.Error("output after stop was captured")
+			}
+		}
+	})
+
+	t.Run("when n < 1", func(t *testing.T) {
+		// ACT
+		stdout, stderr, err := OutputFirst(0, func(stop <-chan struct{}) error {
+			<-stop
+			fmt.Println("ignored")
+			return nil
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", err)
+		}
+		got := [][]string{stdout, stderr}
+		if !reflect.DeepEqual([][]string{nil, nil}, got) {
+			t.Errorf("\nwanted: [[] []]\ngot   : %v", got)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+)
+
+// flush returns a function that calls a supplied function followed by
+// the Flush method of each of the supplied flushers, returning any
+// error returned by the function joined with any errors returned by the
+// flushers.
+func flush(flushers []interface{ Flush() error }, fn func() error) func() error {
+	return func() error {
+		errs := []error{fn()}
+		for _, f := range flushers {
+			errs = append(errs, f.Flush())
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// markWriter is an io.Writer that writes to an underlying writer,
+// removing a marker expected in the written stream and signalling when
+// the marker is written.  This allows the writer of the marker to wait
+// until all output written ahead of it has been written.
+//
+// A marker may be split across writes, so bytes that may be the start
+// of the marker are held back until it is known that they are not.
+type markWriter struct {
+	w      io.Writer
+	mu     sync.Mutex
+	marker []byte
+	held   []byte
+	seen   chan struct{}
+}
+
+// expect sets the marker to be removed from the stream, returning a
+// channel that is closed when the marker has been written.
+func (m *markWriter) expect(marker []byte) <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.marker = marker
+	m.seen = make(chan struct{})
+	return m.seen
+}
+
+// cancel stops removing any expected marker, writing any bytes held
+// back; the channel returned by expect is not closed.
+func (m *markWriter) cancel() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	held := m.held
+	m.marker, m.held = nil, nil
+	_, err := m.w.Write(held)
+	return err
+}
+
+// Write implements io.Writer.
+func (m *markWriter) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.marker == nil {
+		return m.w.Write(p)
+	}
+
+	data := append(m.held, p...)
+	m.held = nil
+
+	if i := bytes.Index(data, m.marker); i >= 0 {
+		rest := data[i+len(m.marker):]
+		m.marker = nil
+		if _, err := m.w.Write(data[:i]); err != nil {
+			return 0, err
+		}
+		close(m.seen)
+		if len(rest) > 0 {
+			if _, err := m.w.Write(rest); err != nil {
+				return 0, err
+			}
+		}
+		return len(p), nil
+	}
+
+	// hold back the longest suffix that is a prefix of the marker
+	n := min(len(data), len(m.marker)-1)
+	for ; n > 0 && !bytes.HasPrefix(m.marker, data[len(data)-n:]); n-- {
+	}
+	m.held = append([]byte(nil), data[len(data)-n:]...)
+	if _, err := m.w.Write(data[:len(data)-n]); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,134 @@
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// stdoutWriter is an io.Writer that writes to the current os.Stdout.
+type stdoutWriter struct{}
+
+func (stdoutWriter) Write(p []byte) (int, error) { return os.Stdout.Write(p) }
+
+// flushError is a flusher that returns an error.
+type flushError struct{ err error }
+
+func (f flushError) Flush() error { return f.err }
+
+func TestWithFlush(t *testing.T) {
+	t.Run("without flush", func(t *testing.T) {
+		// ARRANGE
+		w := bufio.NewWriter(stdoutWriter{})
+
+		// ACT
+		stdout, _, _ := Output(func() error {
+			_, err := fmt.Fprintln(w, "buffered")
+			return err
+		})
+		w.Reset(stdoutWriter{}) // discards the buffered output
+
+		// ASSERT
+		if stdout != nil {
+			t.Errorf("\nwanted: nil\ngot   : %q", stdout)
+		}
+	})
+
+	t.Run("with flush", func(t *testing.T) {
+		// ARRANGE
+		w := bufio.NewWriter(stdoutWriter{})
+
+		// ACT
+		stdout, _, err := Output(func() error {
+			fmt.Println("unbuffered")
+			_, err := fmt.Fprintln(w, "buffered")
+			return err
+		}, WithFlush(w))
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		wanted := []string{"unbuffered", "buffered"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when flush fails", func(t *testing.T) {
+		// ARRANGE
+		fnerr := errors.New("function error")
+		flerr := errors.New("flush error")
+
+		// ACT
+		_, _, err := Output(func() error { return fnerr }, WithFlush(flushError{flerr}))
+
+		// ASSERT
+		for _, wanted := range []error{fnerr, flerr} {
+			if got := err; !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		}
+	})
+}
+
+func TestMarkWriter(t *testing.T) {
+	testcases := []struct {
+		name   string
+		writes []string
+		output string
+		seen   bool
+	}{
+		{name: "marker in one write", writes: []string{"abc<mark>def"}, output: "abcdef", seen: true},
+		{name: "marker split across writes", writes: []string{"abc<ma", "rk>def"}, output: "abcdef", seen: true},
+		{name: "marker in many writes", writes: []string{"<", "m", "a", "r", "k", ">"}, output: "", seen: true},
+		{name: "partial marker", writes: []string{"abc<ma", "x>def"}, output: "abc<max>def"},
+		{name: "repeated partial marker", writes: []string{"<<ma", "<mark>"}, output: "<<ma", seen: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ARRANGE
+			buf := &bytes.Buffer{}
+			m := &markWriter{w: buf}
+			seen := m.expect([]byte("<mark>"))
+
+			// ACT
+			for _, s := range tc.writes {
+				if _, err := m.Write([]byte(s)); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if !tc.seen {
+				_ = m.cancel()
+			}
+
+			// ASSERT
+			t.Run("output", func(t *testing.T) {
+				wanted := tc.output
+				got := buf.String()
+				if wanted != got {
+					t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+				}
+			})
+
+			t.Run("marker seen", func(t *testing.T) {
+				wanted := tc.seen
+				got := false
+				select {
+				case <-seen:
+					got = true
+				default:
+				}
+				if wanted != got {
+					t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package capture
+
+import (
+	"io/fs"
+	"testing/fstest"
+)
+
+// OutputFS captures the stdout and stderr output produced during
+// execution of a supplied function, as for OutputBytes, returning the
+// captured output as the content of the files "stdout" and "stderr" in
+// an in-memory file system.  This allows captured output to be used
+// with code (e.g. assertion helpers or golden file tooling) that works
+// with files.
+//
+// Both files are always present; a file is empty if no output was
+// captured from the stream (or if an error occurred capturing it).
+// Error handling is otherwise identical to OutputBytes.
+//
+// Example:
+//
+//	  func TestReport(t *testing.T) {
+//		fsys, err := capture.OutputFS(func () error {
+//		   return printReport()
+//		})
+//		...
+//		b, _ := fs.ReadFile(fsys, "stdout")
+//	  }
+func OutputFS(fn func() error) (fs.FS, error) {
+	stdout, stderr, err := OutputBytes(fn)
+
+	return fstest.MapFS{
+		"stdout": &fstest.MapFile{Data: stdout, Mode: 0o444},
+		"stderr": &fstest.MapFile{Data: stderr, Mode: 0o444},
+	}, err
+}
@@ -0,0 +1,56 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestOutputFS(t *testing.T) {
+	t.Run("with output", func(t *testing.T) {
+		// ARRANGE
+		fnerr := errors.New("function error")
+
+		// ACT
+		fsys, err := OutputFS(func() error {
+			fmt.Println("to stdout")
+			os.Stderr.WriteString("to stderr")
+			return fnerr
+		})
+
+		// ASSERT
+		if !errors.Is(err, fnerr) {
+			t.Errorf("error:\nwanted: %v\ngot   : %v", fnerr, err)
+		}
+		for name, wanted := range map[string]string{"stdout": "to stdout\n", "stderr": "to stderr"} {
+			got, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", name, err)
+			}
+			if wanted != string(got) {
+				t.Errorf("%s:\nwanted: %q\ngot   : %q", name, wanted, got)
+			}
+		}
+	})
+
+	t.Run("with no output", func(t *testing.T) {
+		// ACT
+		fsys, err := OutputFS(func() error { return nil })
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := fstest.TestFS(fsys, "stdout", "stderr"); err != nil {
+			t.Error(err)
+		}
+		for _, name := range []string{"stdout", "stderr"} {
+			if b, err := fs.ReadFile(fsys, name); err != nil || len(b) != 0 {
+				t.Errorf("%s:\nwanted: <empty>, <nil>\ngot   : %q, %v", name, b, err)
+			}
+		}
+	})
+}
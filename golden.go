@@ -0,0 +1,257 @@
+package capture
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Update causes AssertGolden to write captured output to golden files,
+// rather than comparing it with them.  Update is also implied if the
+// test binary defines a boolean flag named "update" that is set (e.g.
+// `go test -update`); the flag is not defined by this package.
+var Update bool
+
+// AssertGolden captures the stdout and stderr output produced during
+// execution of a supplied function as a single, ordered stream (as for
+// Combined) and compares it, byte-for-byte, with the content of a
+// golden file, failing the test (using t.Errorf) if the captured output
+// is different or if an error is returned.  Differences are reported as
+// a unified diff.
+//
+// If Update is true (or an "update" flag is set; see Update) the
+// captured output is instead written to the golden file, creating the
+// file (and any parent directories) if necessary, unless an error is
+// returned.
+//
+// Example:
+//
+//	  var update = flag.Bool("update", false, "update golden files")
+//
+//	  func TestHelp(t *testing.T) {
+//		capture.AssertGolden(t, "testdata/help.golden", func () error {
+//		   return run([]string{"--help"})
+//		})
+//	  }
+func AssertGolden(t testing.TB, goldenPath string, fn func() error) {
+	t.Helper()
+
+	got, err := combined(io.Copy, fn)
+	if err != nil {
+		t.Errorf("golden %s: unexpected error: %v", goldenPath, err)
+		return
+	}
+
+	if update() {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Errorf("golden %s: %v", goldenPath, err)
+			return
+		}
+		if err := os.WriteFile(goldenPath, got, 0o644); err != nil {
+			t.Errorf("golden %s: %v", goldenPath, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Errorf("golden %s: %v (use -update to create)", goldenPath, err)
+		return
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Errorf("golden %s: output differs:\n%s", goldenPath, unifiedDiff(goldenPath, "captured", string(want), string(got)))
+	}
+}
+
+// update returns true if golden files are to be updated.
+func update() bool {
+	if Update {
+		return true
+	}
+	f := flag.Lookup("update")
+	if f == nil {
+		return false
+	}
+	b, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+	v, ok := b.Get().(bool)
+	return ok && v
+}
+
+// diffOp is a line in a diff: a line common to both texts (' '), or a
+// line removed from ('-') or added to ('+') the first text.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns a unified diff (with 3 lines of context) of two
+// texts, labelled with the supplied names.
+func unifiedDiff(aname, bname, a, b string) string {
+	ops := diffOps(strings.SplitAfter(a, "\n"), strings.SplitAfter(b, "\n"))
+
+	const context = 3
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "--- %s\n+++ %s\n", aname, bname)
+
+	// al and bl are the (0-based) line numbers, in each text, of ops[i]
+	al, bl := 0, 0
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			al, bl, i = al+1, bl+1, i+1
+			continue
+		}
+
+		// a hunk starts with up to context lines preceding a change and
+		// includes any change following within 2*context common lines,
+		// ending with up to context lines following the last change
+		start := max(0, i-context)
+		last := i
+		for j := i + 1; j < len(ops) && j-last <= 2*context; j++ {
+			if ops[j].kind != ' ' {
+				last = j
+			}
+		}
+		end := min(len(ops), last+1+context)
+
+		as, bs := al-(i-start), bl-(i-start)
+		var an, bn int
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				an++
+			}
+			if op.kind != '-' {
+				bn++
+			}
+		}
+		fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", hunkStart(as, an), an, hunkStart(bs, bn), bn)
+		for _, op := range ops[start:end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(strings.TrimSuffix(op.line, "\n"))
+			sb.WriteByte('\n')
+			if !strings.HasSuffix(op.line, "\n") {
+				sb.WriteString("\\ No newline at end of file\n")
+			}
+		}
+
+		for _, op := range ops[i:end] {
+			if op.kind != '+' {
+				al++
+			}
+			if op.kind != '-' {
+				bl++
+			}
+		}
+		i = end
+	}
+
+	return sb.String()
+}
+
+// hunkStart returns the 1-based line number at which a hunk of n lines,
+// starting at the 0-based line s, starts (by convention, a hunk of no
+// lines starts at the line preceding it).
+func hunkStart(s, n int) int {
+	if n == 0 {
+		return s
+	}
+	return s + 1
+}
+
+// maxDiffCells is the maximum size of the table used by diffOps to
+// find the longest common subsequence of two sequences of lines.
+const maxDiffCells = 1 << 20
+
+// diffOps returns the lines common to, removed from and added to two
+// sequences of lines, derived from their longest common subsequence.
+// An empty final element (following a terminating newline) is ignored.
+//
+// Lines common to the start and end of both sequences are matched
+// directly.  The longest common subsequence of the lines in between
+// requires a table of (n+1)*(m+1) entries for n and m lines; if that
+// exceeds maxDiffCells, the lines in between are instead reported as
+// all removed followed by all added.
+func diffOps(a, b []string) []diffOp {
+	if len(a) > 0 && a[len(a)-1] == "" {
+		a = a[:len(a)-1]
+	}
+	if len(b) > 0 && b[len(b)-1] == "" {
+		b = b[:len(b)-1]
+	}
+
+	ops := make([]diffOp, 0, max(len(a), len(b)))
+
+	// common prefix and suffix
+	p := 0
+	for p < len(a) && p < len(b) && a[p] == b[p] {
+		ops = append(ops, diffOp{' ', a[p]})
+		p++
+	}
+	s := 0
+	for s < len(a)-p && s < len(b)-p && a[len(a)-1-s] == b[len(b)-1-s] {
+		s++
+	}
+	suffix := a[len(a)-s:]
+	a, b = a[p:len(a)-s], b[p:len(b)-s]
+
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, l := range a {
+			ops = append(ops, diffOp{'-', l})
+		}
+		for _, l := range b {
+			ops = append(ops, diffOp{'+', l})
+		}
+	} else {
+		ops = lcsOps(ops, a, b)
+	}
+
+	for _, l := range suffix {
+		ops = append(ops, diffOp{' ', l})
+	}
+	return ops
+}
+
+// lcsOps appends to ops the lines common to, removed from and added to
+// two sequences of lines, derived from their longest common
+// subsequence.
+func lcsOps(ops []diffOp, a, b []string) []diffOp {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	return ops
+}
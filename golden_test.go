@@ -0,0 +1,190 @@
+package capture
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAssertGolden(t *testing.T) {
+	// ARRANGE
+	fn := func() error {
+		fmt.Println("line 1")
+		os.Stderr.WriteString("line 2\n")
+		fmt.Println("line 3")
+		return nil
+	}
+
+	t.Run("when output matches", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+		path := filepath.Join(t.TempDir(), "output.golden")
+		_ = os.WriteFile(path, []byte("line 1\nline 2\nline 3\n"), 0o644)
+
+		// ACT
+		AssertGolden(mock, path, fn)
+
+		// ASSERT
+		if len(mock.errors) != 0 {
+			t.Errorf("\nwanted: no errors\ngot   : %v", mock.errors)
+		}
+	})
+
+	t.Run("when output differs", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+		path := filepath.Join(t.TempDir(), "output.golden")
+		_ = os.WriteFile(path, []byte("line 1\nline two\nline 3"), 0o644)
+
+		// ACT
+		AssertGolden(mock, path, fn)
+
+		// ASSERT
+		wanted := []string{"golden " + path + ": output differs:\n" +
+			"--- " + path + "\n" +
+			"+++ captured\n" +
+			"@@ -1,3 +1,3 @@\n" +
+			" line 1\n" +
+			"-line two\n" +
+			"-line 3\n" +
+			"\\ No newline at end of file\n" +
+			"+line 2\n" +
+			"+line 3\n"}
+		got := mock.errors
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when golden file does not exist", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+		path := filepath.Join(t.TempDir(), "output.golden")
+
+		// ACT
+		AssertGolden(mock, path, fn)
+
+		// ASSERT
+		if len(mock.errors) != 1 {
+			t.Errorf("\nwanted: 1 error\ngot   : %q", mock.errors)
+		}
+	})
+
+	t.Run("when function returns an error", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+		path := filepath.Join(t.TempDir(), "output.golden")
+		defer func(og bool) { Update = og }(Update)
+		Update = true
+
+		// ACT
+		AssertGolden(mock, path, func() error { return errors.New("function error") })
+
+		// ASSERT
+		if len(mock.errors) != 1 {
+			t.Errorf("\nwanted: 1 error\ngot   : %q", mock.errors)
+		}
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("golden file updated: %v", err)
+		}
+	})
+
+	t.Run("when updating", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+		path := filepath.Join(t.TempDir(), "testdata", "output.golden")
+		defer func(og bool) { Update = og }(Update)
+		Update = true
+
+		// ACT
+		AssertGolden(mock, path, fn)
+
+		// ASSERT
+		if len(mock.errors) != 0 {
+			t.Errorf("\nwanted: no errors\ngot   : %v", mock.errors)
+		}
+
+		wanted := "line 1\nline 2\nline 3\n"
+		got, _ := os.ReadFile(path)
+		if wanted != string(got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when update flag is set", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+		path := filepath.Join(t.TempDir(), "output.golden")
+		defer func(og *flag.FlagSet) { flag.CommandLine = og }(flag.CommandLine)
+		flag.CommandLine = flag.NewFlagSet("test", flag.ContinueOnError)
+		flag.Bool("update", true, "update golden files")
+
+		// ACT
+		AssertGolden(mock, path, fn)
+
+		// ASSERT
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("golden file not updated: %v", err)
+		}
+	})
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	// ARRANGE
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n15\n16\n"
+
+	// ACT
+	result := unifiedDiff("a", "b", a, b)
+
+	// ASSERT
+	wanted := "--- a\n+++ b\n" +
+		"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+		"@@ -11,5 +11,5 @@\n 11\n 12\n 13\n-14\n 15\n+16\n"
+	got := result
+	if wanted != got {
+		t.Errorf("\nwanted:\n%s\ngot:\n%s", wanted, got)
+	}
+}
+
+func TestDiffOpsLarge(t *testing.T) {
+	// ARRANGE
+	const n = 2000 // (n+1)*(n+1) > maxDiffCells
+	a := []string{"first\n"}
+	b := []string{"first\n"}
+	for i := 0; i < n; i++ {
+		a = append(a, fmt.Sprintf("a%d\n", i))
+		b = append(b, fmt.Sprintf("b%d\n", i))
+	}
+	a = append(a, "last\n")
+	b = append(b, "last\n")
+
+	// ACT
+	ops := diffOps(a, b)
+
+	// ASSERT
+	if len(ops) != 2*n+2 {
+		t.Fatalf("\nwanted: %d ops\ngot   : %d ops", 2*n+2, len(ops))
+	}
+	wanted := "1 common, 2000 removed, 2000 added, 1 common"
+	got := fmt.Sprintf("%d common, %d removed, %d added, %d common",
+		countOps(ops[:1], ' '), countOps(ops[1:n+1], '-'), countOps(ops[n+1:2*n+1], '+'), countOps(ops[2*n+1:], ' '))
+	if wanted != got {
+		t.Errorf("\nwanted: %s\ngot   : %s", wanted, got)
+	}
+}
+
+// countOps returns the number of diff ops of a given kind.
+func countOps(ops []diffOp, kind byte) int {
+	n := 0
+	for _, op := range ops {
+		if op.kind == kind {
+			n++
+		}
+	}
+	return n
+}
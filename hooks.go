@@ -0,0 +1,111 @@
+package capture
+
+import (
+	"bytes"
+	"io"
+)
+
+// OnStart and OnStop are hooks that, if set, are called when any
+// capture starts and stops, whichever function is used to capture
+// output (Output, Stdout, Combined, Suppress, OutputStream, a Capturer
+// etc).  This allows a test framework to trace capture boundaries or
+// collect metrics globally.
+//
+// OnStart is called before os.Stdout and/or os.Stderr (or the file
+// descriptors, for OutputFD, or the variable, for Writer) are
+// redirected.  OnStop is called after
+// they have been restored, with the captured lines and the error
+// returned by the function joined with any capture error.  The lines
+// are provided even if the capture itself returns the output in some
+// other form (or discards it, as for Suppress).  They are all of the
+// output captured, before any limit or other processing (such as
+// WithLimit or WithStripANSI) is applied to the result.
+//
+// For a capture of a single file (Stdout, Stderr or File) the output
+// is provided as the lines of stderr if the file is os.Stderr and as
+// the lines of stdout otherwise; Combined output, and the output of a
+// Writer capture (for which the io.Writer variable is replaced rather
+// than a file), is provided as stdout.
+// The lines of the other stream are nil.  For CaptureManual, for which
+// the output is read by the caller, both are nil.
+//
+// OnStop is not called if the captured function panics.
+//
+// Both hooks are called while the capture lock is held, so that they
+// are serialized with all other captures.  For the same reason they
+// must not capture output themselves (e.g. by calling Output), which
+// would recurse.  The hooks should be set before any captures are
+// started (e.g. in TestMain) and not changed while any capture is in
+// progress.
+//
+// Example:
+//
+//	  func TestMain(m *testing.M) {
+//		capture.OnStop = func(stdout, stderr []string, err error) {
+//		   captured.Add(int64(len(stdout) + len(stderr)))
+//		}
+//		os.Exit(m.Run())
+//	  }
+var (
+	OnStart func()
+	OnStop  func(stdout, stderr []string, err error)
+)
+
+// hook records the output of a capture for any OnStop hook.
+type hook struct {
+	stdout *bytes.Buffer // nil if there is no OnStop hook
+	stderr *bytes.Buffer // nil if there is no OnStop hook
+}
+
+// startHook calls any OnStart hook, returning a hook recording the
+// output of the capture for any OnStop hook.  It must be called while
+// the capture lock is held, before the capture is started.
+func startHook() *hook {
+	if OnStart != nil {
+		OnStart()
+	}
+
+	h := &hook{}
+	if OnStop != nil {
+		h.stdout, h.stderr = &bytes.Buffer{}, &bytes.Buffer{}
+	}
+	return h
+}
+
+// tee returns a writer that writes to the supplied writer, recording the
+// output of a stream for any OnStop hook.  If there is no OnStop hook,
+// the supplied writer is returned.
+func (h *hook) tee(st Stream, w io.Writer) io.Writer {
+	buf := h.buffer(st)
+	if buf == nil {
+		return w
+	}
+	return io.MultiWriter(w, buf)
+}
+
+// record records output of a stream, captured by other means, for any
+// OnStop hook.
+func (h *hook) record(st Stream, p []byte) {
+	if buf := h.buffer(st); buf != nil {
+		buf.Write(p)
+	}
+}
+
+// buffer returns the buffer in which the output of a stream is recorded,
+// or nil if there is no OnStop hook.
+func (h *hook) buffer(st Stream) *bytes.Buffer {
+	if st == StderrStream {
+		return h.stderr
+	}
+	return h.stdout
+}
+
+// stop calls any OnStop hook with the recorded output and the supplied
+// error.  It must be called while the capture lock is held, once the
+// capture is complete.
+func (h *hook) stop(err error) {
+	if OnStop == nil || h.stdout == nil {
+		return
+	}
+	OnStop(lines(h.stdout.String()), lines(h.stderr.String()), err)
+}
@@ -0,0 +1,181 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	// ARRANGE
+	defer func() { OnStart, OnStop = nil, nil }()
+
+	fnerr := errors.New("function error")
+	var (
+		events      []string
+		redirected  bool
+		hookStdout  []string
+		hookStderr  []string
+		hookErr     error
+		ogout       = os.Stdout
+		restoredOut bool
+	)
+	OnStart = func() {
+		events = append(events, "start")
+		redirected = os.Stdout != ogout
+	}
+	OnStop = func(stdout, stderr []string, err error) {
+		events = append(events, "stop")
+		restoredOut = os.Stdout == ogout
+		hookStdout, hookStderr, hookErr = stdout, stderr, err
+	}
+
+	// ACT
+	_, _, _ = Output(func() error {
+		events = append(events, "fn")
+		fmt.Println("to stdout")
+		os.Stderr.WriteString("to stderr\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("called around capture", func(t *testing.T) {
+		wanted := []string{"start", "fn", "stop"}
+		got := events
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("OnStart called before redirecting", func(t *testing.T) {
+		if redirected {
+			t.Error("os.Stdout was redirected")
+		}
+	})
+
+	t.Run("OnStop called after restoring", func(t *testing.T) {
+		if !restoredOut {
+			t.Error("os.Stdout was not restored")
+		}
+	})
+
+	t.Run("OnStop receives result", func(t *testing.T) {
+		wanted := [][]string{{"to stdout"}, {"to stderr"}}
+		got := [][]string{hookStdout, hookStderr}
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+		if !errors.Is(hookErr, fnerr) {
+			t.Errorf("\nwanted: %v\ngot   : %v", fnerr, hookErr)
+		}
+	})
+
+	t.Run("called while capture lock is held", func(t *testing.T) {
+		// ARRANGE
+		var depth int
+		OnStart = func() {
+			mu.mu.Lock()
+			defer mu.mu.Unlock()
+			depth = mu.depth
+		}
+		OnStop = nil
+
+		// ACT
+		_, _, _ = Output(func() error { return nil })
+
+		// ASSERT
+		if depth < 1 {
+			t.Errorf("\nwanted: >= 1\ngot   : %d", depth)
+		}
+	})
+}
+
+func TestHooksEntryPoints(t *testing.T) {
+	// ARRANGE
+	defer func() { OnStart, OnStop = nil, nil }()
+
+	var (
+		starts int
+		stops  int
+		out    []string
+		errs   []string
+	)
+	OnStart = func() { starts++ }
+	OnStop = func(stdout, stderr []string, _ error) {
+		stops++
+		out, errs = stdout, stderr
+	}
+
+	fn := func() error {
+		fmt.Println("to stdout")
+		os.Stderr.WriteString("to stderr\n")
+		return nil
+	}
+
+	testcases := []struct {
+		name   string
+		act    func()
+		stdout []string
+		stderr []string
+	}{
+		{name: "Output", act: func() { _, _, _ = Output(fn) },
+			stdout: []string{"to stdout"}, stderr: []string{"to stderr"}},
+		{name: "Stdout", act: func() { _, _ = Stdout(fn) },
+			stdout: []string{"to stdout"}},
+		{name: "Stderr", act: func() { _, _ = Stderr(fn) },
+			stderr: []string{"to stderr"}},
+		{name: "Combined", act: func() { _, _ = Combined(fn) },
+			stdout: []string{"to stdout", "to stderr"}},
+		{name: "Suppress", act: func() { _ = Suppress(fn) },
+			stdout: []string{"to stdout"}, stderr: []string{"to stderr"}},
+		{name: "OutputStream", act: func() { _ = OutputStream(func(Stream, string) {}, fn) },
+			stdout: []string{"to stdout"}, stderr: []string{"to stderr"}},
+		{name: "OutputStats", act: func() { _, _ = OutputStats(fn) },
+			stdout: []string{"to stdout"}, stderr: []string{"to stderr"}},
+		{name: "OutputToFile", act: func() { _, _, _ = OutputToFile(fn) },
+			stdout: []string{"to stdout"}, stderr: []string{"to stderr"}},
+		{name: "OutputSync", act: func() { _, _, _ = OutputSync(fn) },
+			stdout: []string{"to stdout"}, stderr: []string{"to stderr"}},
+		{name: "OutputBestEffort", act: func() { _, _, _ = OutputBestEffort(fn) },
+			stdout: []string{"to stdout"}, stderr: []string{"to stderr"}},
+		{name: "Capturer", act: func() {
+			c := &Capturer{}
+			_ = c.Start()
+			_ = fn()
+			_, _, _ = c.Stop()
+		}, stdout: []string{"to stdout"}, stderr: []string{"to stderr"}},
+		{name: "Writer", act: func() {
+			var w io.Writer = io.Discard
+			_, _ = Writer(&w, func() error { _, err := fmt.Fprintln(w, "to writer"); return err })
+		}, stdout: []string{"to writer"}},
+		{name: "CaptureManual", act: func() {
+			stdout, stderr, stop, _ := CaptureManual()
+			go func() { _, _ = io.Copy(io.Discard, stdout) }()
+			go func() { _, _ = io.Copy(io.Discard, stderr) }()
+			_ = fn()
+			stop()
+		}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ARRANGE
+			starts, stops, out, errs = 0, 0, nil, nil
+
+			// ACT
+			tc.act()
+
+			// ASSERT
+			if starts != 1 || stops != 1 {
+				t.Errorf("\nwanted: 1 start, 1 stop\ngot   : %d starts, %d stops", starts, stops)
+			}
+			wanted := [][]string{tc.stdout, tc.stderr}
+			got := [][]string{out, errs}
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	}
+}
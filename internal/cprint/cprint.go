@@ -0,0 +1,30 @@
+//go:build cgo
+
+// Package cprint provides functions writing output using C stdio, for
+// use in tests (cgo cannot be used in test files).
+package cprint
+
+// #include <stdio.h>
+// #include <stdlib.h>
+//
+// static void cprintf(const char *s) { printf("%s", s); }
+// static void ceprintf(const char *s) { fprintf(stderr, "%s", s); }
+import "C"
+
+import "unsafe"
+
+// Stdout writes a string to the C stdio stdout stream (using printf),
+// without flushing it.
+func Stdout(s string) {
+	cs := C.CString(s)
+	defer C.free(unsafe.Pointer(cs))
+	C.cprintf(cs)
+}
+
+// Stderr writes a string to the C stdio stderr stream (using fprintf);
+// stderr is unbuffered.
+func Stderr(s string) {
+	cs := C.CString(s)
+	defer C.free(unsafe.Pointer(cs))
+	C.ceprintf(cs)
+}
@@ -0,0 +1,60 @@
+package capture
+
+import (
+	"bytes"
+	"io"
+)
+
+// OutputInto captures the stdout and stderr output produced during
+// execution of a supplied function directly into caller-supplied
+// buffers, returning only the error.
+//
+// Captured output is appended to any existing content of the buffers.
+// A nil buffer discards the output of the corresponding stream.
+// Error handling is as for Output; if ErrStdoutCapture or
+// ErrStderrCapture is returned, the buffer for the stream is truncated
+// to its original length, discarding the captured output.
+//
+// NOTE: bytes.Buffer is not safe for concurrent use and the buffers
+// are written to by a separate goroutine while the capture is in
+// progress.  The buffers must not be accessed (by the function, or
+// any other goroutine) until OutputInto has returned.
+//
+// Of the available options, only WithCopyFunc and WithReadChunkSize
+// apply to OutputInto; any other options are ignored.
+//
+// Example:
+//
+//	  func TestSomething(t *testing.T) {
+//		stdout := &bytes.Buffer{}
+//		err := capture.OutputInto(stdout, nil, func () error {
+//		   return doSomething()
+//		})
+//
+//		fmt.Printf("stdout: %q", stdout.Bytes())
+//		fmt.Printf("error: %v", err)
+//	  }
+func OutputInto(stdout, stderr *bytes.Buffer, fn func() error, opts ...Option) error {
+	outw, outlen := into(stdout)
+	errw, errlen := into(stderr)
+
+	o := redirect(outw, errw, newConfig(opts).copier(), 0, fn)
+
+	if o.stdout != nil && stdout != nil {
+		stdout.Truncate(outlen)
+	}
+	if o.stderr != nil && stderr != nil {
+		stderr.Truncate(errlen)
+	}
+	return o.join()
+}
+
+// into returns the writer to which output captured in a buffer is
+// written, together with the original length of the buffer.  If the
+// buffer is nil, io.Discard is returned.
+func into(buf *bytes.Buffer) (io.Writer, int) {
+	if buf == nil {
+		return io.Discard, 0
+	}
+	return buf, buf.Len()
+}
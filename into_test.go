@@ -0,0 +1,68 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestOutputInto(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	stdout := bytes.NewBufferString("existing\n")
+
+	// ACT
+	err := OutputInto(stdout, nil, func() error {
+		fmt.Println("to stdout")
+		os.Stderr.WriteString("discarded\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout appended", func(t *testing.T) {
+		wanted := "existing\nto stdout\n"
+		got := stdout.String()
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when copying fails", func(t *testing.T) {
+		// ARRANGE
+		cp := func(dst io.Writer, src io.Reader) (int64, error) {
+			_, _ = io.Copy(dst, src)
+			return 0, errors.New("copy error")
+		}
+		stdout := bytes.NewBufferString("existing\n")
+		stderr := &bytes.Buffer{}
+
+		// ACT
+		err := OutputInto(stdout, stderr, func() error {
+			fmt.Println("to stdout")
+			os.Stderr.WriteString("to stderr\n")
+			return nil
+		}, WithCopyFunc(cp))
+
+		// ASSERT
+		if !errors.Is(err, ErrStdoutCapture) || !errors.Is(err, ErrStderrCapture) {
+			t.Errorf("\nwanted: %v and %v\ngot   : %v", ErrStdoutCapture, ErrStderrCapture, err)
+		}
+		if wanted, got := "existing\n", stdout.String(); wanted != got {
+			t.Errorf("stdout:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		if got := stderr.Len(); got != 0 {
+			t.Errorf("stderr:\nwanted: <empty>\ngot   : %q", stderr.String())
+		}
+	})
+}
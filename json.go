@@ -0,0 +1,69 @@
+package capture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// OutputJSON captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, unmarshalling each
+// captured line as JSON into a value of type T.
+//
+// This is intended for code that writes structured (JSON lines) logs,
+// allowing assertions to be made on the fields of the logged records
+// rather than on their text.  Blank lines are ignored.
+//
+// A line that cannot be unmarshalled is omitted from the result; an
+// ErrInvalidJSON error is returned for each such line, identifying the
+// stream and line (and wrapping the unmarshalling error), joined with
+// any error returned by the function and any capture errors.
+//
+// Example:
+//
+//	  type entry struct {
+//		Level string `json:"level"`
+//		Msg   string `json:"msg"`
+//	  }
+//
+//	  func TestLogging(t *testing.T) {
+//		_, stderr, err := capture.OutputJSON[entry](func () error {
+//		   return doSomething() // logs JSON to stderr
+//		})
+//		if stderr[0].Level != "info" {
+//		   ...
+//		}
+//	  }
+func OutputJSON[T any](fn func() error) ([]T, []T, error) {
+	stdout, stderr, err := Output(fn)
+
+	outv, outerr := unmarshalLines[T](StdoutStream, stdout)
+	errv, errerr := unmarshalLines[T](StderrStream, stderr)
+
+	return outv, errv, errors.Join(err, outerr, errerr)
+}
+
+// unmarshalLines unmarshals each of a slice of lines captured from a
+// stream as JSON, returning the values unmarshalled together with an
+// error for any line that could not be unmarshalled.  Blank lines are
+// ignored.  If no values are unmarshalled, the result is nil.
+func unmarshalLines[T any](st Stream, l []string) ([]T, error) {
+	var (
+		result []T
+		errs   []error
+	)
+	for i, s := range l {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+
+		var v T
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s: line %d: %w", ErrInvalidJSON, st, i+1, err))
+			continue
+		}
+		result = append(result, v)
+	}
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,72 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOutputJSON(t *testing.T) {
+	// ARRANGE
+	type entry struct {
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+	}
+	fnerr := errors.New("function error")
+
+	// ACT
+	stdout, stderr, err := OutputJSON[entry](func() error {
+		fmt.Println(`{"level":"info","msg":"started"}`)
+		fmt.Println()
+		os.Stderr.WriteString(`{"level":"error","msg":"failed"}` + "\n")
+		os.Stderr.WriteString("not json\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("stdout", func(t *testing.T) {
+		wanted := []entry{{Level: "info", Msg: "started"}}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %+v\ngot   : %+v", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		wanted := []entry{{Level: "error", Msg: "failed"}}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %+v\ngot   : %+v", wanted, got)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, wanted := range []error{fnerr, ErrInvalidJSON} {
+			if !errors.Is(err, wanted) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, err)
+			}
+		}
+		if wanted := "stderr: line 2:"; !strings.Contains(err.Error(), wanted) {
+			t.Errorf("\nwanted: %q\ngot   : %v", wanted, err)
+		}
+	})
+
+	t.Run("when all lines are valid", func(t *testing.T) {
+		// ACT
+		stdout, stderr, err := OutputJSON[map[string]any](func() error {
+			fmt.Println(`{"a":1}`)
+			return nil
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", err)
+		}
+		if len(stdout) != 1 || stderr != nil {
+			t.Errorf("\nwanted: 1 stdout record, nil stderr\ngot   : %v, %v", stdout, stderr)
+		}
+	})
+}
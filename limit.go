@@ -0,0 +1,56 @@
+package capture
+
+import "io"
+
+// limitWriter is an io.Writer that writes at most max bytes to an
+// underlying writer.  Any bytes in excess of the limit are discarded
+// (but reported as written) and the writer is marked as truncated.
+type limitWriter struct {
+	w         io.Writer
+	remaining int64
+	truncated bool
+}
+
+// Write implements io.Writer.
+func (lw *limitWriter) Write(p []byte) (int, error) {
+	n := int64(len(p))
+	if n > lw.remaining {
+		lw.truncated = true
+		n = lw.remaining
+	}
+	if n > 0 {
+		if _, err := lw.w.Write(p[:n]); err != nil {
+			return 0, err
+		}
+		lw.remaining -= n
+	}
+	return len(p), nil
+}
+
+// OutputLimit captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, retaining at most
+// max bytes of output from each stream.
+//
+// Output in excess of the limit is read (so that the function is never
+// blocked writing to a full pipe) but is discarded.  If the output of
+// either stream is truncated, ErrTruncated is returned, wrapped with
+// the name of the stream, and joined with any other errors.  The final
+// line of truncated output may be incomplete.
+//
+// A max of zero (or less) means no limit.  OutputLimit is equivalent to
+// calling Output with the WithLimit option.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, stderr, err := capture.OutputLimit(1024, func () error {
+//		   return doSomething()
+//		})
+//
+//		if errors.Is(err, capture.ErrTruncated) {
+//		   fmt.Println("output exceeded 1024 bytes")
+//		}
+//	  }
+func OutputLimit(max int64, fn func() error) ([]string, []string, error) {
+	return Output(fn, WithLimit(max))
+}
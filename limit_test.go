@@ -0,0 +1,73 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOutputLimit(t *testing.T) {
+	t.Run("when output is within limit", func(t *testing.T) {
+		// ACT
+		stdout, stderr, err := OutputLimit(16, func() error {
+			fmt.Println("to stdout")
+			os.Stderr.WriteString("to stderr\n")
+			return nil
+		})
+
+		// ASSERT
+		t.Run("no error", func(t *testing.T) {
+			got := err
+			if got != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", got)
+			}
+		})
+
+		t.Run("output captured", func(t *testing.T) {
+			wanted := [][]string{{"to stdout"}, {"to stderr"}}
+			got := [][]string{stdout, stderr}
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+	})
+
+	t.Run("when output exceeds limit", func(t *testing.T) {
+		// ARRANGE
+		large := strings.Repeat("x", 128*1024) // larger than a typical pipe buffer
+
+		// ACT
+		stdout, stderr, err := OutputLimit(8, func() error {
+			fmt.Println("12345678")
+			fmt.Print(large)
+			return nil
+		})
+
+		// ASSERT
+		t.Run("returns ErrTruncated", func(t *testing.T) {
+			wanted := ErrTruncated
+			got := err
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("stdout truncated", func(t *testing.T) {
+			wanted := []string{"12345678"}
+			got := stdout
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+
+		t.Run("stderr is nil", func(t *testing.T) {
+			got := stderr
+			if got != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", got)
+			}
+		})
+	})
+}
@@ -0,0 +1,42 @@
+package capture
+
+import "unicode/utf8"
+
+// splitLong splits any lines longer than max bytes into lines of at
+// most max bytes, avoiding splitting a UTF-8 encoded character where
+// possible.  If max is zero (or less), or no line exceeds max bytes,
+// the lines are returned unchanged.
+func splitLong(l []string, max int) []string {
+	if max <= 0 {
+		return l
+	}
+
+	var result []string
+	for i, s := range l {
+		if len(s) <= max {
+			if result != nil {
+				result = append(result, s)
+			}
+			continue
+		}
+		if result == nil {
+			result = append(make([]string, 0, len(l)+len(s)/max), l[:i]...)
+		}
+		for len(s) > max {
+			n := max
+			for n > 0 && !utf8.RuneStart(s[n]) {
+				n--
+			}
+			if n == 0 {
+				n = max
+			}
+			result = append(result, s[:n])
+			s = s[n:]
+		}
+		result = append(result, s)
+	}
+	if result == nil {
+		return l
+	}
+	return result
+}
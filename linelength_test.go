@@ -0,0 +1,59 @@
+package capture
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitLong(t *testing.T) {
+	testcases := []struct {
+		scenario string
+		lines    []string
+		max      int
+		result   []string
+	}{
+		{scenario: "nil", lines: nil, max: 4, result: nil},
+		{scenario: "no maximum", lines: []string{"abcdefgh"}, max: 0, result: []string{"abcdefgh"}},
+		{scenario: "within maximum", lines: []string{"abcd", "ef"}, max: 4, result: []string{"abcd", "ef"}},
+		{scenario: "exceeds maximum", lines: []string{"ab", "abcdefghij", "cd"}, max: 4, result: []string{"ab", "abcd", "efgh", "ij", "cd"}},
+		{scenario: "empty lines", lines: []string{"", "abcde", ""}, max: 4, result: []string{"", "abcd", "e", ""}},
+		{scenario: "multi-byte characters", lines: []string{"aé€b"}, max: 4, result: []string{"aé", "€b"}},
+		{scenario: "character longer than maximum", lines: []string{"€"}, max: 2, result: []string{"\xe2\x82", "\xac"}},
+		{scenario: "emoji", lines: []string{"😀😀"}, max: 5, result: []string{"😀", "😀"}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			// ACT
+			result := splitLong(tc.lines, tc.max)
+
+			// ASSERT
+			wanted := tc.result
+			got := result
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	}
+}
+
+func TestWithMaxLineLength(t *testing.T) {
+	// ACT
+	stdout, stderr, err := Output(func() error {
+		os.Stdout.WriteString(strings.Repeat("=", 10) + "\r" + strings.Repeat("#", 5) + "\n")
+		os.Stderr.WriteString("short\n")
+		return nil
+	}, WithMaxLineLength(6))
+
+	// ASSERT
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if wanted, got := []string{"======", "====\r#", "####"}, stdout; !reflect.DeepEqual(wanted, got) {
+		t.Errorf("stdout:\nwanted: %q\ngot   : %q", wanted, got)
+	}
+	if wanted, got := []string{"short"}, stderr; !reflect.DeepEqual(wanted, got) {
+		t.Errorf("stderr:\nwanted: %q\ngot   : %q", wanted, got)
+	}
+}
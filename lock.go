@@ -0,0 +1,116 @@
+package capture
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// mu serializes captures.  Since capturing output involves replacing
+// process-global variables (os.Stdout and os.Stderr), only one capture
+// may be active at any time; concurrent captures are queued.
+var mu = newCaptureLock()
+
+// captureLock is a reentrant lock.  Once held by a goroutine, further
+// calls to lock from the same goroutine succeed immediately; the lock
+// is released when unlock has been called once for each call to lock.
+// Attempts to lock from any other goroutine block until the lock is
+// released.
+//
+// A goroutine started by the lock holder using spawn shares ownership
+// of the lock, so that captures nested in a function running on that
+// goroutine do not deadlock.
+//
+// Any other goroutine started while the lock is held is not an owner,
+// even if the holder waits for it.  In particular, a subtest run using
+// t.Run runs on a new goroutine while the parent test waits for it to
+// complete, so a capture started in a subtest while the parent test
+// holds the lock (e.g. a subtest run in a function passed to Output,
+// or while a capture started by Begin is in progress) deadlocks.
+type captureLock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	owners map[uint64]bool
+	depth  int
+}
+
+// newCaptureLock returns a new, unlocked captureLock.
+func newCaptureLock() *captureLock {
+	l := &captureLock{owners: map[uint64]bool{}}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// lock acquires the lock for the calling goroutine, blocking until the
+// lock is available if it is held by any other goroutine.
+func (l *captureLock) lock() {
+	id := goid()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for l.depth > 0 && !l.owners[id] {
+		l.cond.Wait()
+	}
+	l.owners[id] = true
+	l.depth++
+}
+
+// unlock releases one level of the lock held by the calling goroutine.
+func (l *captureLock) unlock() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.depth--
+	if l.depth == 0 {
+		clear(l.owners)
+		l.cond.Broadcast()
+	}
+}
+
+// spawn runs a function on a new goroutine.  If the calling goroutine
+// holds the lock, the new goroutine shares ownership of it; spawn
+// returns once the new goroutine has been registered as an owner, and
+// ownership is relinquished when the function returns.
+func (l *captureLock) spawn(fn func()) {
+	parent := goid()
+	ready := make(chan struct{})
+	go func() {
+		id := goid()
+
+		l.mu.Lock()
+		shared := l.depth > 0 && l.owners[parent]
+		if shared {
+			l.owners[id] = true
+		}
+		l.mu.Unlock()
+		close(ready)
+
+		if shared {
+			defer func() {
+				l.mu.Lock()
+				delete(l.owners, id)
+				l.mu.Unlock()
+			}()
+		}
+
+		fn()
+	}()
+	<-ready
+}
+
+// goid returns the id of the calling goroutine, obtained from the
+// header of the goroutine stack trace ("goroutine <id> [...").
+//
+// The format of the header is not a documented API, and obtaining it
+// costs a stack trace of the calling goroutine on each call; goid is
+// called once for each lock and unlock of the capture lock.
+func goid() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	b = b[:bytes.IndexByte(b, ' ')]
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
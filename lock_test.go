@@ -0,0 +1,66 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentOutput(t *testing.T) {
+	// ARRANGE
+	const n = 8
+	stdout := make([][]string, n)
+	stderr := make([][]string, n)
+	wg := sync.WaitGroup{}
+
+	// ACT
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			stdout[i], stderr[i], _ = Output(func() error {
+				for l := 0; l < 10; l++ {
+					fmt.Printf("stdout %d\n", i)
+					fmt.Fprintf(os.Stderr, "stderr %d\n", i)
+				}
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	// ASSERT
+	for i := 0; i < n; i++ {
+		t.Run(fmt.Sprintf("goroutine %d", i), func(t *testing.T) {
+			check := func(name string, got []string) {
+				wanted := fmt.Sprintf("%s %d", name, i)
+				if len(got) != 10 {
+					t.Errorf("%s: wanted 10 lines, got %d: %v", name, len(got), got)
+				}
+				for _, l := range got {
+					if l != wanted {
+						t.Errorf("%s:\nwanted: %q\ngot   : %q", name, wanted, l)
+					}
+				}
+			}
+			check("stdout", stdout[i])
+			check("stderr", stderr[i])
+		})
+	}
+}
+
+func TestGoid(t *testing.T) {
+	// ARRANGE
+	a := goid()
+	c := make(chan uint64)
+
+	// ACT
+	go func() { c <- goid() }()
+	b := <-c
+
+	// ASSERT
+	if a == 0 || b == 0 || a == b {
+		t.Errorf("\nwanted: distinct, non-zero ids\ngot   : %d, %d", a, b)
+	}
+}
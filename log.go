@@ -0,0 +1,36 @@
+package capture
+
+import (
+	"log"
+	"os"
+)
+
+// OutputWithLog captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, additionally
+// capturing output written using the standard log package.
+//
+// The output of the standard logger is bound to os.Stderr when the
+// log package is initialised, so it is not captured by Output.
+// OutputWithLog redirects the standard logger to the captured stderr
+// for the duration of the call, restoring the previous logger output
+// (whatever it was) when the function returns, or panics.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		_, stderr, _ := capture.OutputWithLog(func () error {
+//		   log.Println("some log output")
+//		   return nil
+//		})
+//
+//		fmt.Printf("stderr: %v", stderr) // [2006/01/02 15:04:05 some log output]
+//	  }
+func OutputWithLog(fn func() error) ([]string, []string, error) {
+	return Output(func() error {
+		og := log.Writer()
+		log.SetOutput(os.Stderr)
+		defer log.SetOutput(og)
+
+		return fn()
+	})
+}
@@ -0,0 +1,57 @@
+package capture
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"testing"
+)
+
+func TestOutputWithLog(t *testing.T) {
+	// ARRANGE
+	logout := &bytes.Buffer{}
+	ogout, ogflags := log.Writer(), log.Flags()
+	defer func() { log.SetOutput(ogout); log.SetFlags(ogflags) }()
+	log.SetOutput(logout)
+	log.SetFlags(0)
+
+	// ACT
+	_, stderr, _ := OutputWithLog(func() error {
+		log.Println("log output")
+		return nil
+	})
+
+	// ASSERT
+	t.Run("log output captured", func(t *testing.T) {
+		wanted := []string{"log output"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("logger output restored", func(t *testing.T) {
+		wanted := logout
+		got := log.Writer()
+		if wanted != got {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("when function panics", func(t *testing.T) {
+		// ARRANGE
+		defer func() {
+			_ = recover()
+
+			// ASSERT
+			wanted := logout
+			got := log.Writer()
+			if wanted != got {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		}()
+
+		// ACT
+		_, _, _ = OutputWithLog(func() error { panic("panic") })
+	})
+}
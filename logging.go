@@ -0,0 +1,61 @@
+package capture
+
+import "testing"
+
+// OutputLogging captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, forwarding the
+// captured lines to the test log (using t.Logf) if the function (or the
+// capture) returns an error or if the test fails.
+//
+// The captured output is logged immediately if an error is returned;
+// otherwise it is logged when the test completes, only if the test has
+// failed (the check is registered using t.Cleanup).  Supplying the
+// WithLogAlways option logs the captured output in all cases.  Each
+// logged line is prefixed by the stream on which it was captured
+// ("stdout: " or "stderr: ").
+//
+// This surfaces the output of a function in the log of a failing test
+// (e.g. in CI) without having to log it explicitly in every test.  The
+// captured lines and error are also returned for explicit assertions.
+// Any other options are applied to the capture as for Output.
+//
+// Example:
+//
+//	  func TestSomething(t *testing.T) {
+//		stdout, _, err := capture.OutputLogging(t, func () error {
+//		   return doSomething()
+//		})
+//		if err != nil {
+//		   t.Fatal(err) // the captured output has already been logged
+//		}
+//		...
+//	  }
+func OutputLogging(t testing.TB, fn func() error, opts ...Option) ([]string, []string, error) {
+	t.Helper()
+
+	cfg := newConfig(opts)
+	stdout, stderr, err := Output(fn, opts...)
+
+	log := func() {
+		t.Helper()
+		for _, s := range stdout {
+			t.Logf("stdout: %s", s)
+		}
+		for _, s := range stderr {
+			t.Logf("stderr: %s", s)
+		}
+	}
+
+	switch {
+	case cfg.logAlways || err != nil:
+		log()
+	default:
+		t.Cleanup(func() {
+			if t.Failed() {
+				log()
+			}
+		})
+	}
+
+	return stdout, stderr, err
+}
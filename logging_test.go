@@ -0,0 +1,87 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputLogging(t *testing.T) {
+	// ARRANGE
+	fn := func(err error) func() error {
+		return func() error {
+			fmt.Println("to stdout")
+			os.Stderr.WriteString("to stderr\n")
+			return err
+		}
+	}
+	logged := []string{"stdout: to stdout", "stderr: to stderr"}
+
+	t.Run("when an error is returned", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+		fnerr := errors.New("function error")
+
+		// ACT
+		stdout, stderr, err := OutputLogging(mock, fn(fnerr))
+
+		// ASSERT
+		if !errors.Is(err, fnerr) {
+			t.Errorf("error:\nwanted: %v\ngot   : %v", fnerr, err)
+		}
+		if wanted, got := [][]string{{"to stdout"}, {"to stderr"}}, [][]string{stdout, stderr}; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("output:\nwanted: %v\ngot   : %v", wanted, got)
+		}
+		if wanted, got := logged, mock.logs; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("logs:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when the test fails", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+
+		// ACT
+		_, _, _ = OutputLogging(mock, fn(nil))
+		logs := mock.logs
+		mock.failed = true
+		mock.cleanup()
+
+		// ASSERT
+		if logs != nil {
+			t.Errorf("logged before test completed: %q", logs)
+		}
+		if wanted, got := logged, mock.logs; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("logs:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when the test passes", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+
+		// ACT
+		_, _, _ = OutputLogging(mock, fn(nil))
+		mock.cleanup()
+
+		// ASSERT
+		if got := mock.logs; got != nil {
+			t.Errorf("logs:\nwanted: nil\ngot   : %q", got)
+		}
+	})
+
+	t.Run("WithLogAlways", func(t *testing.T) {
+		// ARRANGE
+		mock := &mockT{TB: t}
+
+		// ACT
+		_, _, _ = OutputLogging(mock, fn(nil), WithLogAlways())
+
+		// ASSERT
+		if wanted, got := logged, mock.logs; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("logs:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package capture
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// CaptureManual redirects os.Stdout and os.Stderr to pipes, returning
+// readers from which the output written to each may be read and a
+// function that restores os.Stdout and os.Stderr.  This is the
+// primitive on which the other capture functions are built, without any
+// management of the lifecycle of the capture.
+//
+// The caller is responsible for:
+//
+//   - calling the restore function; until it is called, os.Stdout and
+//     os.Stderr remain redirected and any capture on another goroutine
+//     blocks.  The restore function may be called from any goroutine
+//     and more than once; only the first call has any effect.
+//
+//   - reading both readers, concurrently with the output being written;
+//     once the buffer of a pipe is full, any write to the redirected file
+//     blocks until the pipe is read.  The readers return io.EOF once
+//     the capture has been restored and all captured output read, at
+//     which point the pipe is closed; a pipe whose reader is not read to
+//     io.EOF (or an error) is not closed, leaking its file descriptor.
+//
+// If a pipe cannot be created, nothing is redirected and ErrStdoutCapture
+// or ErrStderrCapture is returned, wrapping ErrPipeCreate.
+//
+// Example:
+//
+//	  func TestLowLevel(t *testing.T) {
+//		stdout, stderr, restore, err := capture.CaptureManual()
+//		if err != nil {
+//		   t.Fatal(err)
+//		}
+//		go io.Copy(io.Discard, stderr)
+//
+//		done := make(chan []byte)
+//		go func() { b, _ := io.ReadAll(stdout); done <- b }()
+//
+//		fmt.Println("some output")
+//		restore()
+//
+//		fmt.Printf("stdout: %q", <-done) // "some output\n"
+//	  }
+func CaptureManual() (io.Reader, io.Reader, func(), error) {
+	mu.lock()
+
+	// the output is read by the caller, so none is recorded for the hook
+	h := startHook()
+
+	outr, outw, err := pipeFn()
+	if err != nil {
+		err = fmt.Errorf("%w: %w: %w", ErrStdoutCapture, ErrPipeCreate, err)
+		h.stop(err)
+		mu.unlock()
+		return nil, nil, nil, err
+	}
+
+	errr, errw, err := pipeFn()
+	if err != nil {
+		_ = outr.Close()
+		_ = outw.Close()
+		err = fmt.Errorf("%w: %w: %w", ErrStderrCapture, ErrPipeCreate, err)
+		h.stop(err)
+		mu.unlock()
+		return nil, nil, nil, err
+	}
+
+	rdout := install(&os.Stdout, outw)
+	rderr := install(&os.Stderr, errw)
+
+	var once sync.Once
+	restore := func() {
+		once.Do(func() {
+			// see capture
+			_, _ = outw.Write(nil)
+			_, _ = errw.Write(nil)
+			rderr.restore()
+			rdout.restore()
+			_ = outw.Close()
+			_ = errw.Close()
+			h.stop(nil)
+			mu.unlock()
+		})
+	}
+
+	return &eofCloser{f: outr}, &eofCloser{f: errr}, restore, nil
+}
+
+// eofCloser is an io.Reader that reads from a file, closing the file
+// once a read returns an error (including io.EOF).
+type eofCloser struct {
+	f   *os.File
+	err error
+}
+
+// Read implements io.Reader.
+func (r *eofCloser) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.f.Read(p)
+	if err != nil {
+		r.err = err
+		_ = r.f.Close()
+	}
+	return n, err
+}
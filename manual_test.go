@@ -0,0 +1,73 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestCaptureManual(t *testing.T) {
+	t.Run("captures until restored", func(t *testing.T) {
+		// ARRANGE
+		og := os.Stdout
+		stdout, stderr, restore, err := CaptureManual()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer restore()
+
+		outc := make(chan []byte)
+		errc := make(chan []byte)
+		go func() { b, _ := io.ReadAll(stdout); outc <- b }()
+		go func() { b, _ := io.ReadAll(stderr); errc <- b }()
+
+		// ACT
+		fmt.Println("to stdout")
+		os.Stderr.WriteString("to stderr\n")
+		restore()
+		restore() // no effect
+
+		// ASSERT
+		if wanted, got := "to stdout\n", string(<-outc); wanted != got {
+			t.Errorf("stdout:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		if wanted, got := "to stderr\n", string(<-errc); wanted != got {
+			t.Errorf("stderr:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		if os.Stdout != og {
+			t.Error("stdout not restored")
+		}
+		if _, err := stdout.Read(make([]byte, 1)); !errors.Is(err, io.EOF) {
+			t.Errorf("read after EOF:\nwanted: %v\ngot   : %v", io.EOF, err)
+		}
+	})
+
+	t.Run("when pipe cannot be created", func(t *testing.T) {
+		// ARRANGE
+		pipeerr := errors.New("pipe error")
+		og := pipeFn
+		defer func() { pipeFn = og }()
+		pipeFn = func() (*os.File, *os.File, error) { return nil, nil, pipeerr }
+
+		// ACT
+		_, _, restore, err := CaptureManual()
+
+		// ASSERT
+		for _, wanted := range []error{ErrStdoutCapture, ErrPipeCreate, pipeerr} {
+			if got := err; !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		}
+		if restore != nil {
+			t.Error("restore function returned")
+		}
+
+		// the capture lock is not held
+		pipeFn = og
+		if _, _, err := Output(func() error { return nil }); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
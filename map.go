@@ -0,0 +1,42 @@
+package capture
+
+// mapLines applies a transformation to each of a slice of lines,
+// dropping any line for which the transformation returns "".  If no
+// lines remain, nil is returned.
+func mapLines(l []string, transform func(string) string) []string {
+	var result []string
+	for _, s := range l {
+		if s = transform(s); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// OutputMap captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, applying a supplied
+// transformation to each captured line of both streams.
+//
+// If the transformation returns "" for a line, the line is dropped; a
+// transformation may therefore be used to filter as well as to
+// normalise the captured output (e.g. removing timestamps or masking
+// ids).  If no lines remain for a stream, the result for that stream
+// is nil.
+//
+// Error handling is identical to Output.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, _, _ := capture.OutputMap(
+//		   func(s string) string { return strings.TrimPrefix(s, "DEBUG: ") },
+//		   func () error {
+//		      return doSomething()
+//		   })
+//
+//		fmt.Printf("stdout: %v", stdout)
+//	  }
+func OutputMap(transform func(line string) string, fn func() error) ([]string, []string, error) {
+	stdout, stderr, err := Output(fn)
+	return mapLines(stdout, transform), mapLines(stderr, transform), err
+}
@@ -0,0 +1,53 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOutputMap(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	transform := func(s string) string {
+		if strings.HasPrefix(s, "DEBUG") {
+			return ""
+		}
+		return strings.ToUpper(s)
+	}
+
+	// ACT
+	stdout, stderr, err := OutputMap(transform, func() error {
+		fmt.Println("to stdout")
+		fmt.Println("DEBUG: dropped")
+		os.Stderr.WriteString("DEBUG: dropped\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout transformed", func(t *testing.T) {
+		wanted := []string{"TO STDOUT"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stderr is nil", func(t *testing.T) {
+		got := stderr
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package capture
+
+// OutputMerged captures the stdout and stderr output produced during
+// execution of a supplied function, returning the lines of both streams
+// in a single slice in the order in which they were captured.
+//
+// OutputMerged is OutputTagged without the stream identification, and
+// the same ordering caveats apply.  Unlike Combined (which redirects
+// both os.Stdout and os.Stderr to the same pipe, providing stricter
+// ordering), os.Stdout and os.Stderr remain distinct files during the
+// capture, so code that treats the two differently behaves as normal.
+//
+// If there is no output, the returned slice is nil.
+//
+// Example:
+//
+//	  func TestTranscript(t *testing.T) {
+//		transcript, err := capture.OutputMerged(func () error {
+//		   return runCommand()
+//		})
+//		...
+//	  }
+func OutputMerged(fn func() error) ([]string, error) {
+	tagged, err := OutputTagged(fn)
+
+	var result []string
+	for _, l := range tagged {
+		result = append(result, l.Text)
+	}
+
+	return result, err
+}
@@ -0,0 +1,59 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestOutputMerged(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	var distinct bool
+
+	// ACT
+	result, err := OutputMerged(func() error {
+		distinct = os.Stdout != os.Stderr
+		fmt.Println("to stdout")
+		os.Stderr.WriteString("to stderr\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("lines captured", func(t *testing.T) {
+		wanted := []string{"to stderr", "to stdout"}
+		got := append([]string(nil), result...)
+		sort.Strings(got) // relative ordering across streams is best-effort
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("streams are distinct", func(t *testing.T) {
+		if !distinct {
+			t.Error("os.Stdout and os.Stderr were the same file")
+		}
+	})
+
+	t.Run("when no output is produced", func(t *testing.T) {
+		// ACT
+		result, _ := OutputMerged(func() error { return nil })
+
+		// ASSERT
+		got := result
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package capture
+
+// MustOutput captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, panicking if the
+// output cannot be captured.
+//
+// Only errors arising from the capture itself cause a panic, with the
+// error (wrapping ErrStdoutCapture and/or ErrStderrCapture) as the
+// panic value.  The error returned by the function is not a capture
+// error, even if it wraps one (e.g. from a nested capture); it is
+// returned, with the captured output, and does not cause a panic.
+//
+// This is useful in tests where a capture failure is not expected
+// and need not be handled, leaving only the error returned by the
+// function to be checked.
+//
+// Example:
+//
+//	  func TestDoSomething(t *testing.T) {
+//		stdout, _, err := capture.MustOutput(func () error {
+//		   return doSomething()
+//		})
+//		if err != nil {
+//		   t.Fatal(err)
+//		}
+//		...
+//	  }
+func MustOutput(fn func() error) ([]string, []string, error) {
+	stdout, stderr, fnerr, err := outputSeparate(fn)
+	if err != nil {
+		panic(err)
+	}
+
+	return stdout, stderr, fnerr
+}
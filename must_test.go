@@ -0,0 +1,65 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestMustOutput(t *testing.T) {
+	t.Run("when function returns an error", func(t *testing.T) {
+		// ARRANGE
+		fnerr := fmt.Errorf("nested: %w", ErrStdoutCapture)
+
+		// ACT
+		stdout, stderr, err := MustOutput(func() error {
+			fmt.Println("to stdout")
+			os.Stderr.WriteString("to stderr\n")
+			return fnerr
+		})
+
+		// ASSERT
+		t.Run("returns function error", func(t *testing.T) {
+			wanted := fnerr
+			got := err
+			if wanted != got {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+
+		t.Run("output captured", func(t *testing.T) {
+			wanted := [][]string{{"to stdout"}, {"to stderr"}}
+			got := [][]string{stdout, stderr}
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	})
+
+	t.Run("when output cannot be captured", func(t *testing.T) {
+		// ARRANGE
+		pipeerr := errors.New("too many open files")
+		og := pipeFn
+		defer func() { pipeFn = og }()
+		pipeFn = func() (*os.File, *os.File, error) { return nil, nil, pipeerr }
+
+		defer func() {
+			// ASSERT
+			r := recover()
+			err, ok := r.(error)
+			if !ok {
+				t.Fatalf("\nwanted: panic with error\ngot   : %#v", r)
+			}
+			for _, wanted := range []error{ErrStdoutCapture, ErrStderrCapture, pipeerr} {
+				if !errors.Is(err, wanted) {
+					t.Errorf("\nwanted: %v\ngot   : %v", wanted, err)
+				}
+			}
+		}()
+
+		// ACT
+		_, _, _ = MustOutput(func() error { return nil })
+	})
+}
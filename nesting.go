@@ -0,0 +1,80 @@
+package capture
+
+import (
+	"os"
+	"sync"
+)
+
+// redirection records the replacement of a file with one end of
+// a pipe, together with the file that was replaced.
+type redirection struct {
+	t  **os.File
+	w  *os.File
+	og *os.File
+}
+
+var (
+	// redirections holds the active redirections of each captured file,
+	// in the order in which they were installed.
+	redirections   = map[**os.File][]*redirection{}
+	redirectionsMu sync.Mutex
+)
+
+// install replaces a file with the supplied end of a pipe, returning a
+// redirection that must be restored when the capture is complete.
+func install(t **os.File, w *os.File) *redirection {
+	redirectionsMu.Lock()
+	defer redirectionsMu.Unlock()
+
+	r := &redirection{t: t, w: w, og: *t}
+	*t = w
+	redirections[t] = append(redirections[t], r)
+
+	return r
+}
+
+// restore removes the redirection.
+//
+// Nested captures are normally restored in the reverse order in which
+// they were installed, with each capture restoring the file that it
+// replaced.  If a capture is restored while a nested capture remains
+// active (e.g. an OutputContext capture that is cancelled while a
+// capture in the abandoned function is still in progress) the file is
+// left redirected to the nested capture; the nested capture will
+// instead restore the file that the outer capture replaced.
+func (r *redirection) restore() {
+	redirectionsMu.Lock()
+	defer redirectionsMu.Unlock()
+
+	s := redirections[r.t]
+	for i, ri := range s {
+		if ri != r {
+			continue
+		}
+		if i == len(s)-1 {
+			*r.t = r.og
+		} else {
+			s[i+1].og = r.og
+		}
+		s = append(s[:i], s[i+1:]...)
+		break
+	}
+
+	if len(s) == 0 {
+		delete(redirections, r.t)
+		return
+	}
+	redirections[r.t] = s
+}
+
+// original returns the file that a captured file referred to before
+// any capture was installed.
+func original(t **os.File) *os.File {
+	redirectionsMu.Lock()
+	defer redirectionsMu.Unlock()
+
+	if s := redirections[t]; len(s) > 0 {
+		return s[0].og
+	}
+	return *t
+}
@@ -0,0 +1,150 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestNestedOutput(t *testing.T) {
+	// ARRANGE
+	ogout, ogerr := os.Stdout, os.Stderr
+	var middle, inner []string
+
+	// ACT
+	outer, _, err := Output(func() error {
+		fmt.Println("outer (1)")
+		middle, _, _ = Output(func() error {
+			fmt.Println("middle (1)")
+			inner, _, _ = Output(func() error {
+				fmt.Println("inner")
+				return nil
+			})
+			fmt.Println("middle (2)")
+			return nil
+		})
+		fmt.Println("outer (2)")
+		return nil
+	})
+
+	// ASSERT
+	t.Run("no error", func(t *testing.T) {
+		got := err
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+
+	t.Run("outer capture", func(t *testing.T) {
+		wanted := []string{"outer (1)", "outer (2)"}
+		got := outer
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("middle capture", func(t *testing.T) {
+		wanted := []string{"middle (1)", "middle (2)"}
+		got := middle
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("inner capture", func(t *testing.T) {
+		wanted := []string{"inner"}
+		got := inner
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("streams restored", func(t *testing.T) {
+		if os.Stdout != ogout || os.Stderr != ogerr {
+			t.Errorf("os.Stdout and/or os.Stderr not restored")
+		}
+	})
+
+	t.Run("nested in OutputContext", func(t *testing.T) {
+		// ACT
+		var inner []string
+		outer, _, err := OutputContext(context.Background(), func() error {
+			fmt.Println("outer")
+			inner, _, _ = Output(func() error {
+				fmt.Println("inner")
+				return nil
+			})
+			return nil
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", err)
+		}
+
+		wanted := [][]string{{"outer"}, {"inner"}}
+		got := [][]string{outer, inner}
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("when OutputContext is cancelled during a nested capture", func(t *testing.T) {
+		// ARRANGE
+		ctx, cancel := context.WithCancel(context.Background())
+		release := make(chan struct{})
+		done := make(chan struct{})
+
+		// ACT
+		_, _, err := OutputContext(ctx, func() error {
+			defer close(done)
+			_, _, _ = Output(func() error {
+				cancel()
+				<-release
+				return nil
+			})
+			return nil
+		})
+		close(release)
+		<-done
+
+		// ASSERT
+		t.Run("returns context error", func(t *testing.T) {
+			wanted := context.Canceled
+			got := err
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("streams restored", func(t *testing.T) {
+			if os.Stdout != ogout || os.Stderr != ogerr {
+				t.Errorf("os.Stdout and/or os.Stderr not restored")
+			}
+		})
+	})
+}
+
+func TestOriginal(t *testing.T) {
+	// ARRANGE
+	og := os.Stdout
+	var inner, outer *os.File
+
+	// ACT
+	_, _, _ = Output(func() error {
+		outer = original(&os.Stdout)
+		_, _, _ = Output(func() error {
+			inner = original(&os.Stdout)
+			return nil
+		})
+		return nil
+	})
+
+	// ASSERT
+	if outer != og || inner != og {
+		t.Errorf("\nwanted: %v, %v\ngot   : %v, %v", og, og, outer, inner)
+	}
+}
@@ -0,0 +1,35 @@
+package capture
+
+import "bytes"
+
+// OutputNL captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, additionally
+// reporting whether the output of each stream was terminated by a
+// newline.
+//
+// Output cannot distinguish output that is terminated by a newline
+// from output that is not (e.g. "a\nb" and "a\nb\n" both result in
+// ["a" "b"]).  OutputNL returns the same lines, together with a flag
+// for each stream that is true if the captured output ended with "\n".
+// If there is no output for a stream, the flag is false.
+//
+// Error handling is identical to Output.
+//
+// Example:
+//
+//	  func TestFinalNewline(t *testing.T) {
+//		stdout, nl, _, _, err := capture.OutputNL(func () error {
+//		   fmt.Print("no newline")
+//		   return nil
+//		})
+//
+//		fmt.Printf("stdout: %v", stdout) // [no newline]
+//		fmt.Printf("newline: %v", nl)    // false
+//		fmt.Printf("error: %v", err)
+//	  }
+func OutputNL(fn func() error) ([]string, bool, []string, bool, error) {
+	stdout, stderr, err := output(fn)
+	return lines(string(stdout)), bytes.HasSuffix(stdout, []byte("\n")),
+		lines(string(stderr)), bytes.HasSuffix(stderr, []byte("\n")),
+		err
+}
@@ -0,0 +1,57 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputNL(t *testing.T) {
+	// ARRANGE
+	testcases := []struct {
+		name   string
+		output string
+		lines  []string
+		nl     bool
+	}{
+		{name: "no output", output: "", lines: nil, nl: false},
+		{name: "terminated", output: "a\nb\n", lines: []string{"a", "b"}, nl: true},
+		{name: "not terminated", output: "a\nb", lines: []string{"a", "b"}, nl: false},
+		{name: "crlf terminated", output: "a\r\n", lines: []string{"a"}, nl: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ARRANGE
+			fnerr := errors.New("function error")
+
+			// ACT
+			stdout, stdoutNL, stderr, stderrNL, err := OutputNL(func() error {
+				fmt.Print(tc.output)
+				os.Stderr.WriteString(tc.output)
+				return fnerr
+			})
+
+			// ASSERT
+			if !errors.Is(err, fnerr) {
+				t.Errorf("error:\nwanted: %v\ngot   : %v", fnerr, err)
+			}
+			for _, s := range []struct {
+				name  string
+				lines []string
+				nl    bool
+			}{
+				{name: "stdout", lines: stdout, nl: stdoutNL},
+				{name: "stderr", lines: stderr, nl: stderrNL},
+			} {
+				if !reflect.DeepEqual(tc.lines, s.lines) {
+					t.Errorf("%s:\nwanted: %q\ngot   : %q", s.name, tc.lines, s.lines)
+				}
+				if tc.nl != s.nl {
+					t.Errorf("%s newline:\nwanted: %v\ngot   : %v", s.name, tc.nl, s.nl)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package capture
+
+import (
+	"sort"
+	"strings"
+)
+
+// normalizer returns a strings.Replacer applying the supplied literal
+// replacements, with longer strings replaced in preference to shorter
+// ones (and strings of equal length in lexical order).  Empty strings
+// are ignored.  If there are no replacements, nil is returned.
+func normalizer(replacements map[string]string) *strings.Replacer {
+	keys := make([]string, 0, len(replacements))
+	for k := range replacements {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	pairs := make([]string, 0, 2*len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k, replacements[k])
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// normalizeLines applies a replacer to each of a slice of lines.
+func normalizeLines(l []string, r *strings.Replacer) []string {
+	if r == nil {
+		return l
+	}
+	for i, s := range l {
+		l[i] = r.Replace(s)
+	}
+	return l
+}
+
+// OutputNormalize captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, replacing each
+// occurrence of the keys of a map with the corresponding value in the
+// captured lines.  This allows output that varies by environment (e.g.
+// an absolute path of the working directory) to be normalized, for
+// comparison with output captured elsewhere (e.g. in a golden file).
+//
+// Replacements are literal and are applied to each line once the output
+// has been captured in full, in a single pass: replaced text is not
+// itself subject to further replacement.  Map iteration is unordered, so
+// where keys overlap (one key is a prefix of, or found within, another)
+// the replacement is made deterministic by preferring the longest key
+// that matches at each position; keys of equal length are considered in
+// lexical order.  An empty key is ignored.  Replacements do not match
+// across lines.
+//
+// Error handling is identical to Output.
+//
+// Example:
+//
+//	  func TestBuild(t *testing.T) {
+//		wd, _ := os.Getwd()
+//		stdout, _, _ := capture.OutputNormalize(map[string]string{wd: "<wd>"}, func () error {
+//		   return build()
+//		})
+//		t.Log(stdout) // [wrote <wd>/bin/app]
+//	  }
+func OutputNormalize(replacements map[string]string, fn func() error) ([]string, []string, error) {
+	stdout, stderr, err := Output(fn)
+
+	r := normalizer(replacements)
+	return normalizeLines(stdout, r), normalizeLines(stderr, r), err
+}
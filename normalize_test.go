@@ -0,0 +1,61 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputNormalize(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	replacements := map[string]string{
+		"/home/user":         "<home>",
+		"/home/user/project": "<wd>",
+		"":                   "ignored",
+		"<wd>":               "not replaced again",
+	}
+
+	// ACT
+	stdout, stderr, err := OutputNormalize(replacements, func() error {
+		fmt.Println("wrote /home/user/project/bin/app")
+		fmt.Println("config: /home/user/.apprc")
+		fmt.Println()
+		os.Stderr.WriteString("warning: /home/user/project/go.mod\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		wanted := []string{"wrote <wd>/bin/app", "config: <home>/.apprc", ""}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		wanted := []string{"warning: <wd>/go.mod"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("with no replacements", func(t *testing.T) {
+		stdout, _, _ := OutputNormalize(nil, func() error { fmt.Println("unchanged"); return nil })
+		if wanted, got := []string{"unchanged"}, stdout; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
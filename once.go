@@ -0,0 +1,62 @@
+package capture
+
+import (
+	"slices"
+	"sync"
+)
+
+// onceResult holds the memoized result of a capture by OutputOnce.
+type onceResult struct {
+	once   sync.Once
+	stdout []string
+	stderr []string
+	err    error
+}
+
+var (
+	onceResults   = map[string]*onceResult{}
+	onceResultsMu sync.Mutex
+)
+
+// OutputOnce captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, memoizing the result
+// for the lifetime of the process: subsequent calls with the same key
+// return the same result without calling the function again.
+//
+// This is intended for expensive (e.g. setup) functions whose output is
+// asserted in many tests or subtests.  If OutputOnce is called
+// concurrently with the same key, the function is called only once;
+// other callers wait for the capture to complete.  Each caller receives
+// its own copy of the captured lines.
+//
+// NOTE: the memoized result is only correct if the function is
+// deterministic and free of side effects (other than its output); the
+// function is not called for any but the first call with a given key.
+// The key identifies the result, not the function: calls with the same
+// key but a different function return the result of the first.
+//
+// Example:
+//
+//	  func TestSetup(t *testing.T) {
+//		t.Run("reports version", func(t *testing.T) {
+//		   stdout, _, _ := capture.OutputOnce("setup", expensiveSetup)
+//		   ...
+//		})
+//		t.Run("reports config", func(t *testing.T) {
+//		   stdout, _, _ := capture.OutputOnce("setup", expensiveSetup) // not called again
+//		   ...
+//		})
+//	  }
+func OutputOnce(key string, fn func() error) ([]string, []string, error) {
+	onceResultsMu.Lock()
+	r, ok := onceResults[key]
+	if !ok {
+		r = &onceResult{}
+		onceResults[key] = r
+	}
+	onceResultsMu.Unlock()
+
+	r.once.Do(func() { r.stdout, r.stderr, r.err = Output(fn) })
+
+	return slices.Clone(r.stdout), slices.Clone(r.stderr), r.err
+}
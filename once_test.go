@@ -0,0 +1,86 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOutputOnce(t *testing.T) {
+	// ARRANGE
+	key := fmt.Sprintf("%s@%d", t.Name(), time.Now().UnixNano()) // unique if run with -count > 1
+	fnerr := errors.New("function error")
+	calls := 0
+	fn := func() error {
+		calls++
+		fmt.Println("to stdout")
+		os.Stderr.WriteString("to stderr\n")
+		return fnerr
+	}
+
+	// ACT
+	stdout, stderr, err := OutputOnce(key, fn)
+	stdout[0] = "modified"
+	again, _, againErr := OutputOnce(key, fn)
+
+	// ASSERT
+	t.Run("function called once", func(t *testing.T) {
+		wanted := 1
+		got := calls
+		if wanted != got {
+			t.Errorf("\nwanted: %d\ngot   : %d", wanted, got)
+		}
+	})
+
+	t.Run("returns error", func(t *testing.T) {
+		for _, got := range []error{err, againErr} {
+			if !errors.Is(got, fnerr) {
+				t.Errorf("\nwanted: %v\ngot   : %v", fnerr, got)
+			}
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		wanted := []string{"to stderr"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("result not shared", func(t *testing.T) {
+		wanted := []string{"to stdout"}
+		got := again
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("concurrent calls", func(t *testing.T) {
+		// ARRANGE
+		calls := 0
+		fn := func() error { calls++; fmt.Println("output"); return nil }
+		wg := &sync.WaitGroup{}
+
+		// ACT
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, _ = OutputOnce(key+"/concurrent", fn)
+			}()
+		}
+		wg.Wait()
+
+		// ASSERT
+		wanted := 1
+		got := calls
+		if wanted != got {
+			t.Errorf("\nwanted: %d\ngot   : %d", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,444 @@
+package capture
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Option is a function that configures a capture.
+type Option func(*config)
+
+// config holds the configuration of a capture, established by applying
+// any options supplied to the capture function.
+type config struct {
+	stdoutTee io.Writer
+	stderrTee io.Writer
+	limit     int64 // 0 == no limit
+	stripANSI bool
+	locker    sync.Locker
+	stdin     io.Reader
+	blocked   time.Duration // 0 == no block warning
+	copy      copyFunc      // nil == io.Copy
+	logAlways bool
+	flushers  []interface{ Flush() error }
+	spill     int64           // 0 == no spill
+	maxLine   int             // 0 == no maximum
+	drain     time.Duration   // 0 == no drain timeout
+	chunk     int             // 0 == no read chunk size
+	cancel    <-chan struct{} // closed if the function is abandoned
+	size      int             // 0 == no initial buffer size
+}
+
+// newConfig returns a config with the supplied options applied.
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// sink is the writer to which the output of a captured stream is
+// written.
+type sink struct {
+	io.Writer
+	limit *limitWriter
+	spill *spillWriter
+}
+
+// truncated returns true if the output written to the sink exceeded
+// any configured limit.
+func (s sink) truncated() bool {
+	return s.limit != nil && s.limit.truncated
+}
+
+// complete reads any output spilled to a temporary file into the buffer
+// in which the stream is captured, removing the file.
+func (s sink) complete() error {
+	if s.spill == nil {
+		return nil
+	}
+	return s.spill.complete()
+}
+
+// discard removes any temporary file to which output has been spilled
+// without reading it, for a capture that did not complete (e.g. if the
+// function panicked).  Once complete has been called, discard has no
+// effect.
+func (s sink) discard() {
+	if s.spill != nil {
+		s.spill.discard()
+	}
+}
+
+// writer returns the sink for a stream captured in the supplied buffer,
+// applying any initial buffer size, spill threshold, limit, tee writer
+// and block warning.
+func (c *config) writer(st Stream, buf *bytes.Buffer, tee io.Writer) sink {
+	if c.size > 0 {
+		// bytes.Buffer.ReadFrom grows the buffer unless bytes.MinRead
+		// bytes are available, including for the read that returns EOF
+		buf.Grow(c.size + bytes.MinRead)
+	}
+
+	s := sink{Writer: buf}
+	if c.spill > 0 {
+		s.spill = &spillWriter{buf: buf, threshold: c.spill}
+		s.Writer = s.spill
+	}
+	if c.limit > 0 {
+		s.limit = &limitWriter{w: s.Writer, remaining: c.limit}
+		s.Writer = s.limit
+	}
+	if tee != nil {
+		s.Writer = io.MultiWriter(s.Writer, tee)
+	}
+	s.Writer = c.watch(st, s.Writer)
+	return s
+}
+
+// watch returns a writer that reports a diagnostic if a write to the
+// supplied writer blocks for longer than any configured block warning
+// duration.  If no block warning is configured, the supplied writer is
+// returned.
+func (c *config) watch(st Stream, w io.Writer) io.Writer {
+	if c.blocked <= 0 {
+		return w
+	}
+	return &watchdog{w: w, d: c.blocked, stream: st}
+}
+
+// copier returns the function used to copy captured output, reading
+// from the pipe in chunks of any configured read chunk size.
+func (c *config) copier() copyFunc {
+	cp := c.copy
+	if cp == nil {
+		cp = io.Copy
+	}
+	if c.chunk <= 0 {
+		return cp
+	}
+	return func(dst io.Writer, src io.Reader) (int64, error) {
+		return cp(dst, &chunkReader{r: src, n: c.chunk})
+	}
+}
+
+// chunkReader is an io.Reader that reads at most n bytes at a time from
+// an underlying reader.
+type chunkReader struct {
+	r io.Reader
+	n int
+}
+
+// Read implements io.Reader.
+func (cr *chunkReader) Read(p []byte) (int, error) {
+	if len(p) > cr.n {
+		p = p[:cr.n]
+	}
+	return cr.r.Read(p)
+}
+
+// result applies any post-processing to captured output.
+func (c *config) result(b []byte) []byte {
+	if c.stripANSI {
+		b = stripANSI(b)
+	}
+	return b
+}
+
+// syncWriter is an io.Writer that serializes writes to an underlying
+// writer.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Write implements io.Writer.
+func (sw *syncWriter) Write(p []byte) (int, error) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return sw.w.Write(p)
+}
+
+// WithTee writes the captured output of both stdout and stderr to the
+// supplied writer, in addition to capturing it.  Writes to the writer
+// are serialized, so the writer need not be safe for concurrent use.
+//
+// The tee receives all output, as it is captured, regardless of any
+// limit (WithLimit) or post-processing (WithStripANSI).
+func WithTee(w io.Writer) Option {
+	return func(c *config) {
+		sw := &syncWriter{w: w}
+		c.stdoutTee = sw
+		c.stderrTee = sw
+	}
+}
+
+// withTees writes the captured output of stdout and stderr to separate
+// writers.  A nil writer is ignored.
+func withTees(stdout, stderr io.Writer) Option {
+	return func(c *config) {
+		c.stdoutTee = stdout
+		c.stderrTee = stderr
+	}
+}
+
+// WithLimit retains at most n bytes of the captured output of each
+// stream.  Output in excess of the limit is read (so that the captured
+// function is never blocked) but discarded; if either stream exceeds
+// the limit ErrTruncated is returned.  A limit of zero (or less) means
+// no limit.
+func WithLimit(n int64) Option {
+	return func(c *config) { c.limit = n }
+}
+
+// WithSpillThreshold limits the captured output of each stream held in
+// memory while the capture is in progress to n bytes.  Once n bytes of
+// output have been captured from a stream, further output is written to
+// a temporary file, which is read back (and removed) when the capture is
+// complete.
+//
+// The full output is still returned, so the option does not reduce the
+// memory required to hold the result; it avoids the repeated growth
+// (and copying) of an in-memory buffer while a large volume of output
+// is captured, with the result being read into a buffer of the required
+// size.  If the temporary file cannot be created, written or read,
+// ErrStdoutCapture or ErrStderrCapture is returned, wrapping the error,
+// and the captured output of the stream is discarded.
+//
+// A threshold of zero (or less) means output is never spilled.
+func WithSpillThreshold(n int64) Option {
+	return func(c *config) { c.spill = n }
+}
+
+// WithInitialBufferSize allocates the buffer in which the output of
+// each stream is captured with room for at least n bytes before the
+// capture starts, avoiding the repeated growth (and copying) of the
+// buffer while the output is captured.  This is a tuning option for
+// functions known to produce roughly n bytes of output; the buffer
+// still grows as required if more output is captured.
+//
+// A size of zero (or less) means the buffers are grown only as
+// required, as if the option were not used.
+//
+// Example:
+//
+//	  func BenchmarkReport(b *testing.B) {
+//		for i := 0; i < b.N; i++ {
+//		   _, _, _ = capture.Output(func () error {
+//		      return writeReport() // writes ~64 KiB
+//		   }, capture.WithInitialBufferSize(64 << 10))
+//		}
+//	  }
+func WithInitialBufferSize(n int) Option {
+	return func(c *config) { c.size = n }
+}
+
+// WithMaxLineLength limits the length of the lines returned by Output
+// (and OutputResult) to n bytes.  A line longer than n bytes is split
+// into as many entries as required, each of at most n bytes; a split
+// does not divide a multi-byte UTF-8 encoded character unless the
+// character is itself longer than n bytes.
+//
+// Lines are split by length only after the captured output has been
+// split into lines at each newline ("\n"); any "\r" preceding a newline
+// is removed before the line length is considered.  Output containing
+// no newlines (such as a progress display that uses "\r" to overwrite a
+// line) is a single line, split only by length.  A maximum of zero (or
+// less) means no maximum.
+func WithMaxLineLength(n int) Option {
+	return func(c *config) { c.maxLine = n }
+}
+
+// WithStripANSI removes any ANSI escape sequences (e.g. color codes)
+// from the captured output.
+func WithStripANSI() Option {
+	return func(c *config) { c.stripANSI = true }
+}
+
+// WithMutex holds the supplied lock for the duration of the capture.
+//
+// Captures are always serialized with respect to each other; WithMutex
+// additionally serializes a capture with any other code using the same
+// lock, such as a logger or progress reporter that writes to os.Stdout
+// or os.Stderr from another goroutine.
+func WithMutex(m sync.Locker) Option {
+	return func(c *config) { c.locker = m }
+}
+
+// WithStdin replaces os.Stdin for the duration of the capture with a
+// pipe from which the supplied input may be read.  Once the input has
+// been read, reads from os.Stdin return io.EOF.  The original os.Stdin
+// is restored when the capture is complete.
+//
+// WithStdin is an option, rather than a capture function accepting the
+// input and the function to be called, so that input may be supplied to
+// any capture accepting options (e.g. Output, OutputContext or
+// OutputResult) and combined with any other options.
+//
+// Example:
+//
+//	  func TestPrompt(t *testing.T) {
+//		stdout, _, err := capture.Output(func () error {
+//		   return askName() // reads a name from os.Stdin
+//		}, capture.WithStdin("Alice\n"))
+//		...
+//	  }
+func WithStdin(input string) Option {
+	return func(c *config) { c.stdin = strings.NewReader(input) }
+}
+
+// WithStdinReader replaces os.Stdin for the duration of the capture
+// with a pipe from which input supplied by a reader may be read, as for
+// WithStdin.  Reads from os.Stdin return io.EOF once the reader returns
+// io.EOF (or any other error).
+//
+// Input is read from the reader as it becomes available, so a reader
+// such as an io.Pipe may be used to supply input to an interactive
+// function while the capture is in progress.  If a cancellable capture
+// (OutputContext) is cancelled while the function is waiting for input,
+// the pipe is closed and the function reads io.EOF.
+//
+// Example:
+//
+//	  func TestPrompt(t *testing.T) {
+//		pr, pw := io.Pipe()
+//		go func() {
+//		   defer pw.Close()
+//		   waitForPrompt()
+//		   io.WriteString(pw, "Alice\n")
+//		}()
+//
+//		stdout, _, err := capture.Output(func () error {
+//		   return askName() // reads a name from os.Stdin
+//		}, capture.WithStdinReader(pr))
+//		...
+//	  }
+func WithStdinReader(r io.Reader) Option {
+	return func(c *config) { c.stdin = r }
+}
+
+// withCancel identifies a channel that is closed if the function is
+// abandoned before it returns (by OutputContext), closing any stdin
+// pipe (WithStdin) so that a function waiting for input reads io.EOF.
+func withCancel(done <-chan struct{}) Option {
+	return func(c *config) { c.cancel = done }
+}
+
+// WithReadChunkSize limits each read of captured output from the pipe
+// of each stream to at most n bytes, so that output is delivered to any
+// tee writer, OutputStream callback or reader (and to the copy function,
+// if WithCopyFunc is also used) in chunks of at most n bytes.  This
+// allows the handling of partial reads by code consuming captured
+// output to be exercised.
+//
+// Only the granularity of reads is affected; the captured output is
+// identical regardless of the chunk size.  A chunk size of zero (or
+// less) means reads are not limited.
+func WithReadChunkSize(n int) Option {
+	return func(c *config) { c.chunk = n }
+}
+
+// WithDrainTimeout limits the time for which a capture waits, once the
+// captured function has returned, for the captured output of each
+// stream to be copied.
+//
+// Copying completes once the pipe to which a stream is redirected has
+// been closed and drained; if the pipe is held open by another process
+// or file descriptor (e.g. a child process started by the function that
+// inherited the pipe and is still running), or if copying is stalled
+// (e.g. by a blocked tee writer), the capture would otherwise wait
+// indefinitely.
+//
+// If the output of a stream has not been copied within the timeout, the
+// copy is abandoned (any further output is discarded) and the output
+// captured until then is returned, together with ErrDrainTimeout
+// wrapped with ErrStdoutCapture or ErrStderrCapture.  A timeout of zero
+// (or less) means no timeout.
+func WithDrainTimeout(d time.Duration) Option {
+	return func(c *config) { c.drain = d }
+}
+
+// WithBlockWarning reports a diagnostic if no progress is made draining
+// a captured stream for the specified duration.
+//
+// A captured function is blocked when writing output if the pipe buffer
+// is full, which occurs if captured output is not being consumed: for
+// example, if an OutputReader reader is not being read, or an
+// OutputStream callback or a tee writer is slow or has itself blocked.
+// The diagnostic identifies the blocked stream, to help diagnose a
+// capture that appears to hang.
+//
+// The diagnostic is written to the original os.Stderr, as it was before
+// any capture was started (i.e. not to the captured stderr).  The
+// option has no effect on captures that do not block.
+func WithBlockWarning(d time.Duration) Option {
+	return func(c *config) { c.blocked = d }
+}
+
+// WithCopyFunc configures the function used to copy captured output
+// from the pipe of each stream (the reader) to the writer in which it
+// is captured.  The default is io.Copy.
+//
+// A copy function may be used to instrument or throttle the capture,
+// or to simulate a failure to capture output.  If the function returns
+// an error, the corresponding ErrStdoutCapture or ErrStderrCapture is
+// returned, wrapping the error.  A copy function should continue to
+// read from the reader until io.EOF (or an error) even if it stops
+// writing, since a captured function writing output is otherwise
+// blocked once the pipe buffer is full.
+//
+// Example:
+//
+//	  func TestCopyFailure(t *testing.T) {
+//		_, _, err := capture.Output(doSomething, capture.WithCopyFunc(
+//		   func(dst io.Writer, src io.Reader) (int64, error) {
+//		      _, _ = io.Copy(io.Discard, src)
+//		      return 0, errors.New("copy failed")
+//		   }))
+//		...
+//	  }
+func WithCopyFunc(fn func(dst io.Writer, src io.Reader) (int64, error)) Option {
+	return func(c *config) { c.copy = fn }
+}
+
+// WithLogAlways causes OutputLogging to log the captured output in all
+// cases, rather than only if an error is returned or the test fails.
+// The option has no effect on other capture functions.
+func WithLogAlways() Option {
+	return func(c *config) { c.logAlways = true }
+}
+
+// WithFlush calls the Flush method of each of the supplied flushers
+// (e.g. a bufio.Writer) after the captured function returns, before
+// the capture is complete, so that any output buffered by the flushers
+// is captured.  Flushers are flushed in the order supplied; any errors
+// returned are joined with any error returned by the function.
+//
+// It is the caller's responsibility to supply the flushers holding any
+// buffered output.  Output flushed by a flusher is captured only if the
+// flusher writes to the captured os.Stdout or os.Stderr when flushed; a
+// bufio.Writer wrapping os.Stdout itself, established before the capture
+// is started, writes to the original os.Stdout, not the capture.
+//
+// Example:
+//
+//	  // stdout writes to os.Stdout as it is when written to, so that a
+//	  // writer wrapping it writes to the captured os.Stdout
+//	  type stdout struct{}
+//
+//	  func (stdout) Write(p []byte) (int, error) { return os.Stdout.Write(p) }
+//
+//	  func TestReport(t *testing.T) {
+//		w := bufio.NewWriter(stdout{})
+//		lines, _, err := capture.Output(func () error {
+//		   return writeReport(w) // does not flush w
+//		}, capture.WithFlush(w))
+//		...
+//	  }
+func WithFlush(flushers ...interface{ Flush() error }) Option {
+	return func(c *config) { c.flushers = append(c.flushers, flushers...) }
+}
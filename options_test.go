@@ -0,0 +1,249 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestOutputOptions(t *testing.T) {
+	t.Run("no options", func(t *testing.T) {
+		// ACT
+		stdout, stderr, err := Output(func() error {
+			fmt.Println("\x1b[1mto stdout\x1b[0m")
+			os.Stderr.WriteString("to stderr")
+			return nil
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", err)
+		}
+
+		wanted := [][]string{{"\x1b[1mto stdout\x1b[0m"}, {"to stderr"}}
+		got := [][]string{stdout, stderr}
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("WithTee", func(t *testing.T) {
+		// ARRANGE
+		tee := &bytes.Buffer{}
+
+		// ACT
+		stdout, stderr, _ := Output(func() error {
+			fmt.Println("to stdout")
+			os.Stderr.WriteString("to stderr\n")
+			return nil
+		}, WithTee(tee))
+
+		// ASSERT
+		t.Run("output captured", func(t *testing.T) {
+			wanted := [][]string{{"to stdout"}, {"to stderr"}}
+			got := [][]string{stdout, stderr}
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+
+		t.Run("output tee'd", func(t *testing.T) {
+			got := tee.String()
+			if len(got) != 20 || !strings.Contains(got, "to stdout\n") || !strings.Contains(got, "to stderr\n") {
+				t.Errorf("\nwanted: \"to stdout\\n\" and \"to stderr\\n\"\ngot   : %q", got)
+			}
+		})
+	})
+
+	t.Run("WithLimit", func(t *testing.T) {
+		// ACT
+		stdout, _, err := Output(func() error {
+			fmt.Println("12345678")
+			return nil
+		}, WithLimit(4))
+
+		// ASSERT
+		if !errors.Is(err, ErrTruncated) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", ErrTruncated, err)
+		}
+
+		wanted := []string{"1234"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("WithStripANSI", func(t *testing.T) {
+		// ACT
+		stdout, _, _ := Output(func() error {
+			fmt.Println("\x1b[1mbold\x1b[0m")
+			return nil
+		}, WithStripANSI())
+
+		// ASSERT
+		wanted := []string{"bold"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("WithMutex", func(t *testing.T) {
+		// ARRANGE
+		m := &sync.Mutex{}
+		locked := false
+
+		// ACT
+		_, _, _ = Output(func() error {
+			locked = !m.TryLock()
+			return nil
+		}, WithMutex(m))
+
+		// ASSERT
+		t.Run("held during capture", func(t *testing.T) {
+			if !locked {
+				t.Error("mutex was not held")
+			}
+		})
+
+		t.Run("released after capture", func(t *testing.T) {
+			if !m.TryLock() {
+				t.Error("mutex was not released")
+			}
+		})
+	})
+
+	t.Run("WithCopyFunc", func(t *testing.T) {
+		// ARRANGE
+		var (
+			calls  int
+			copied int64
+			cmu    sync.Mutex
+		)
+		cpy := func(dst io.Writer, src io.Reader) (int64, error) {
+			n, err := io.Copy(dst, src)
+			cmu.Lock()
+			defer cmu.Unlock()
+			calls++
+			copied += n
+			return n, err
+		}
+
+		// ACT
+		stdout, stderr, err := Output(func() error {
+			fmt.Print("stdout")
+			os.Stderr.WriteString("stderr")
+			return nil
+		}, WithCopyFunc(cpy))
+
+		// ASSERT
+		t.Run("output captured", func(t *testing.T) {
+			wanted := [][]string{{"stdout"}, {"stderr"}}
+			got := [][]string{stdout, stderr}
+			if err != nil || !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %v, <nil>\ngot   : %v, %v", wanted, got, err)
+			}
+		})
+
+		t.Run("used for both streams", func(t *testing.T) {
+			wanted := []int64{2, 12}
+			got := []int64{int64(calls), copied}
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %d calls, %d bytes\ngot   : %d calls, %d bytes", wanted[0], wanted[1], got[0], got[1])
+			}
+		})
+
+		t.Run("applies only to the configured capture", func(t *testing.T) {
+			// ACT
+			_, _, _ = Output(func() error { fmt.Print("output"); return nil })
+
+			// ASSERT
+			wanted := 2
+			got := calls
+			if wanted != got {
+				t.Errorf("\nwanted: %d calls\ngot   : %d calls", wanted, got)
+			}
+		})
+	})
+
+	t.Run("WithInitialBufferSize", func(t *testing.T) {
+		// ARRANGE
+		content := strings.Repeat("0123456789abcdef\n", 1024)
+		fn := func() error {
+			os.Stdout.WriteString(content)
+			os.Stderr.WriteString("to stderr")
+			return nil
+		}
+
+		for _, n := range []int{-1, 0, 16, len(content), 1 << 20} {
+			t.Run(fmt.Sprintf("%d bytes", n), func(t *testing.T) {
+				// ACT
+				stdout, stderr, err := output(fn, WithInitialBufferSize(n))
+
+				// ASSERT
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if wanted, got := content, string(stdout); wanted != got {
+					t.Errorf("\nwanted: %d bytes\ngot   : %d bytes", len(wanted), len(got))
+				}
+				if wanted, got := "to stderr", string(stderr); wanted != got {
+					t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+				}
+			})
+		}
+	})
+
+	t.Run("WithReadChunkSize", func(t *testing.T) {
+		// ARRANGE
+		content := strings.Repeat("0123456789abcdef\n", 1024) + "no newline"
+		fn := func() error {
+			os.Stdout.WriteString(content)
+			os.Stderr.WriteString(content)
+			return nil
+		}
+		wanted, _, _ := output(fn)
+
+		for _, n := range []int{1, 3, 7, 64, 4096} {
+			t.Run(fmt.Sprintf("%d bytes", n), func(t *testing.T) {
+				// ARRANGE
+				var (
+					largest int
+					cmu     sync.Mutex
+				)
+				tee := writerFunc(func(p []byte) (int, error) {
+					cmu.Lock()
+					defer cmu.Unlock()
+					largest = max(largest, len(p))
+					return len(p), nil
+				})
+
+				// ACT
+				stdout, stderr, err := output(fn, WithReadChunkSize(n), WithTee(tee))
+
+				// ASSERT
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if !bytes.Equal(wanted, stdout) || !bytes.Equal(wanted, stderr) {
+					t.Errorf("captured output differs:\nstdout: %d bytes\nstderr: %d bytes", len(stdout), len(stderr))
+				}
+				if largest > n {
+					t.Errorf("\nwanted: reads of at most %d bytes\ngot   : %d bytes", n, largest)
+				}
+			})
+		}
+	})
+}
+
+// writerFunc is an io.Writer implemented by a function.
+type writerFunc func(p []byte) (int, error)
+
+func (fn writerFunc) Write(p []byte) (int, error) { return fn(p) }
@@ -0,0 +1,49 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// OutputErr captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, returning the
+// captured stdout output and an error that includes any captured
+// stderr output if the function returned an error.  This is useful
+// for functions that report the reason for a failure on stderr.
+//
+// If the function returns an error and stderr output was captured, the
+// returned error wraps the function error with the captured stderr
+// lines, joined by "; ", as context:
+//
+//	fmt.Errorf("%w: %s", err, strings.Join(stderr, "; "))
+//
+// e.g. "operation failed: file not found; aborted".  If the function
+// returns nil, or no stderr output was captured, any error returned by
+// the function is returned unchanged; any stderr output is discarded.
+//
+// Any capture error (ErrStdoutCapture and/or ErrStderrCapture) is
+// joined with the function error.
+//
+// Example:
+//
+//	  func TestDoSomething(t *testing.T) {
+//		stdout, err := capture.OutputErr(func () error {
+//		   return doSomething()
+//		})
+//		if err != nil {
+//		   t.Fatal(err) // operation failed: <stderr lines>
+//		}
+//		...
+//	  }
+func OutputErr(fn func() error) ([]string, error) {
+	stdout, stderr, fnerr, err := outputSeparate(fn)
+
+	if fnerr != nil && len(stderr) > 0 {
+		fnerr = fmt.Errorf("%w: %s", fnerr, strings.Join(stderr, "; "))
+	}
+	if err != nil {
+		return stdout, errors.Join(fnerr, err)
+	}
+	return stdout, fnerr
+}
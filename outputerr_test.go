@@ -0,0 +1,93 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputErr(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("operation failed")
+
+	testcases := []struct {
+		name   string
+		fn     func() error
+		err    error
+		errmsg string
+	}{
+		{name: "when function returns an error with stderr output",
+			fn: func() error {
+				fmt.Println("to stdout")
+				os.Stderr.WriteString("file not found\naborted\n")
+				return fnerr
+			},
+			err:    fnerr,
+			errmsg: "operation failed: file not found; aborted",
+		},
+		{name: "when function returns an error without stderr output",
+			fn: func() error {
+				fmt.Println("to stdout")
+				return fnerr
+			},
+			err:    fnerr,
+			errmsg: "operation failed",
+		},
+		{name: "when function returns nil with stderr output",
+			fn: func() error {
+				fmt.Println("to stdout")
+				os.Stderr.WriteString("warning\n")
+				return nil
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ACT
+			stdout, err := OutputErr(tc.fn)
+
+			// ASSERT
+			t.Run("stdout", func(t *testing.T) {
+				wanted := []string{"to stdout"}
+				got := stdout
+				if !reflect.DeepEqual(wanted, got) {
+					t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+				}
+			})
+
+			t.Run("error", func(t *testing.T) {
+				if tc.err == nil {
+					if err != nil {
+						t.Errorf("\nwanted: nil\ngot   : %v", err)
+					}
+					return
+				}
+				if !errors.Is(err, tc.err) {
+					t.Errorf("\nwanted: %v\ngot   : %v", tc.err, err)
+				}
+				if wanted, got := tc.errmsg, err.Error(); wanted != got {
+					t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+				}
+			})
+		})
+	}
+
+	t.Run("when output cannot be captured", func(t *testing.T) {
+		// ARRANGE
+		og := pipeFn
+		defer func() { pipeFn = og }()
+		pipeFn = func() (*os.File, *os.File, error) { return nil, nil, errors.New("too many open files") }
+
+		// ACT
+		_, err := OutputErr(func() error { return fnerr })
+
+		// ASSERT
+		for _, wanted := range []error{fnerr, ErrStdoutCapture, ErrStderrCapture} {
+			if !errors.Is(err, wanted) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, err)
+			}
+		}
+	})
+}
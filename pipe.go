@@ -0,0 +1,64 @@
+package capture
+
+import (
+	"errors"
+	"io"
+)
+
+// OutputPipe captures the stdout and stderr output produced during
+// execution of a supplied function as a single, ordered stream (as for
+// Combined), returning a reader from which the output may be read as it
+// is produced.  This allows captured output to be supplied directly to
+// a consumer of an io.Reader, such as a decoder or the stdin of another
+// process.
+//
+// The function is run on a separate goroutine; OutputPipe returns
+// immediately.  The returned channel receives the error returned by the
+// function (joined with any ErrStdoutCapture and ErrStderrCapture
+// errors) once the function has returned and all output has been read;
+// the channel is then closed.  The reader returns io.EOF once all
+// captured output has been read, or the capture error if an error
+// occurred while capturing the output.
+//
+// The captured output is not buffered, other than in the pipe to which
+// os.Stdout and os.Stderr are redirected: a function writing output
+// blocks once the pipe buffer is full until the output is read, so a
+// slow reader slows the function.  The caller MUST read the reader to
+// completion and close it.  Closing the reader before all output has
+// been read causes any further output to be discarded, with
+// ErrStdoutCapture and ErrStderrCapture returned (wrapping
+// io.ErrClosedPipe) on the channel.
+//
+// Example:
+//
+//	  func TestPipeline(t *testing.T) {
+//		r, done := capture.OutputPipe(func () error {
+//		   return produce()
+//		})
+//		defer r.Close()
+//
+//		cmd := exec.Command("consumer")
+//		cmd.Stdin = r
+//		if err := cmd.Run(); err != nil {
+//		   t.Fatal(err)
+//		}
+//
+//		if err := <-done; err != nil {
+//		   t.Fatal(err)
+//		}
+//	  }
+func OutputPipe(fn func() error) (io.ReadCloser, <-chan error) {
+	r, w := io.Pipe()
+	done := make(chan error, 1)
+
+	mu.spawn(func() {
+		defer close(done)
+
+		err, cerr := combineTo(w, io.Copy, fn)
+		_ = w.CloseWithError(cerr)
+
+		done <- errors.Join(err, cerr)
+	})
+
+	return r, done
+}
@@ -0,0 +1,82 @@
+package capture
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestOutputPipe(t *testing.T) {
+	t.Run("when read to completion", func(t *testing.T) {
+		// ARRANGE
+		fnerr := errors.New("function error")
+
+		// ACT
+		r, done := OutputPipe(func() error {
+			fmt.Println("to stdout")
+			os.Stderr.WriteString("to stderr\n")
+			fmt.Println("to stdout again")
+			return fnerr
+		})
+		output, rerr := io.ReadAll(r)
+		_ = r.Close()
+		err := <-done
+
+		// ASSERT
+		t.Run("output", func(t *testing.T) {
+			if rerr != nil {
+				t.Errorf("unexpected read error: %v", rerr)
+			}
+
+			wanted := "to stdout\nto stderr\nto stdout again\n"
+			got := string(output)
+			if wanted != got {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+
+		t.Run("returns error", func(t *testing.T) {
+			wanted := fnerr
+			got := err
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+
+		t.Run("channel closed", func(t *testing.T) {
+			if _, ok := <-done; ok {
+				t.Error("channel not closed")
+			}
+		})
+	})
+
+	t.Run("when reader is closed early", func(t *testing.T) {
+		// ARRANGE
+		closed := make(chan struct{})
+
+		// ACT
+		r, done := OutputPipe(func() error {
+			fmt.Println("first line")
+			<-closed
+			fmt.Println("discarded")
+			return nil
+		})
+		line, _ := bufio.NewReader(r).ReadString('\n')
+		_ = r.Close()
+		close(closed)
+		err := <-done
+
+		// ASSERT
+		if wanted, got := "first line\n", line; wanted != got {
+			t.Errorf("output:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		for _, wanted := range []error{ErrStdoutCapture, ErrStderrCapture, io.ErrClosedPipe} {
+			if got := err; !errors.Is(got, wanted) {
+				t.Errorf("error:\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package capture
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBuffer is the capacity above which a buffer is not returned
+// to the pool, so that a single capture of a large volume of output does
+// not pin that memory for the life of the process.
+const maxPooledBuffer = 64 << 10
+
+var buffers = sync.Pool{New: func() any { return &bytes.Buffer{} }}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	return buffers.Get().(*bytes.Buffer)
+}
+
+// putBuffer resets a buffer and returns it to the pool.  The content of
+// the buffer must not be referenced once it has been returned.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	buf.Reset()
+	buffers.Put(buf)
+}
+
+// OutputReusing captures the stdout and stderr output produced during
+// execution of a supplied function into caller-supplied buffers.
+//
+// Each buffer is reset before the capture; when OutputReusing returns
+// the buffers hold the verbatim output written to the corresponding
+// stream.  Error handling is as for Output; if ErrStdoutCapture or
+// ErrStderrCapture is returned the corresponding buffer is reset,
+// discarding any captured output.
+//
+// OutputReusing is intended for hot paths, such as benchmarks or tests
+// that capture output many thousands of times: re-using the same
+// buffers for each call avoids both the allocation of new buffers and
+// of the lines that would be returned by Output.  For most tests the
+// additional bookkeeping is not worthwhile and Output should be
+// preferred.
+//
+// Options are applied as for Output.
+//
+// Example:
+//
+//	  func BenchmarkDoSomething(b *testing.B) {
+//		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+//		for i := 0; i < b.N; i++ {
+//		   if err := capture.OutputReusing(stdout, stderr, doSomething); err != nil {
+//		      b.Fatal(err)
+//		   }
+//		}
+//	  }
+func OutputReusing(stdout, stderr *bytes.Buffer, fn func() error, opts ...Option) error {
+	stdout.Reset()
+	stderr.Reset()
+
+	so, se, err := outputTo(stdout, stderr, fn, opts...)
+	if so == nil {
+		stdout.Reset()
+	}
+	if se == nil {
+		stderr.Reset()
+	}
+	return err
+}
@@ -0,0 +1,155 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestOutputReusing(t *testing.T) {
+	// ARRANGE
+	stdout := bytes.NewBufferString("stale stdout")
+	stderr := bytes.NewBufferString("stale stderr")
+	fnerr := errors.New("function error")
+
+	// ACT
+	err := OutputReusing(stdout, stderr, func() error {
+		fmt.Println("to stdout")
+		os.Stderr.WriteString("to stderr\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		wanted := "to stdout\n"
+		got := stdout.String()
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		wanted := "to stderr\n"
+		got := stderr.String()
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when copying fails", func(t *testing.T) {
+		// ARRANGE
+		cp := func(dst io.Writer, src io.Reader) (int64, error) {
+			_, _ = io.Copy(dst, src)
+			return 0, errors.New("copy error")
+		}
+
+		// ACT
+		err := OutputReusing(stdout, stderr, func() error { fmt.Println("some output"); return nil }, WithCopyFunc(cp))
+
+		// ASSERT
+		if !errors.Is(err, ErrStdoutCapture) {
+			t.Errorf("\nwanted: %v\ngot   : %v", ErrStdoutCapture, err)
+		}
+		if stdout.Len() != 0 {
+			t.Errorf("\nwanted: <empty>\ngot   : %q", stdout.String())
+		}
+	})
+}
+
+func TestPutBuffer(t *testing.T) {
+	t.Run("large buffers are not pooled", func(t *testing.T) {
+		// ARRANGE
+		buf := getBuffer()
+		buf.Grow(maxPooledBuffer + 1)
+		buf.WriteString("content")
+
+		// ACT
+		putBuffer(buf)
+
+		// ASSERT
+		wanted := "content"
+		got := buf.String()
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("pooled buffers are reset", func(t *testing.T) {
+		// ARRANGE
+		buf := getBuffer()
+		buf.WriteString("content")
+
+		// ACT
+		putBuffer(buf)
+
+		// ASSERT
+		wanted := 0
+		got := buf.Len()
+		if wanted != got {
+			t.Errorf("\nwanted: %d\ngot   : %d", wanted, got)
+		}
+	})
+}
+
+// benchmarkOutput is the output written by the function captured in
+// the benchmarks; enough to require a buffer to grow several times.
+var benchmarkOutput = strings.Repeat("some benchmark output\n", 200)
+
+func benchmarkFn() error {
+	_, err := os.Stdout.WriteString(benchmarkOutput)
+	return err
+}
+
+// BenchmarkOutputBytes captures into newly allocated buffers on each
+// call, as Output did before buffers were pooled.
+func BenchmarkOutputBytes(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := OutputBytes(benchmarkFn); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkOutputBytesWithInitialBufferSize captures as for
+// BenchmarkOutputBytes, with buffers initially sized for the output.
+func BenchmarkOutputBytesWithInitialBufferSize(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := output(benchmarkFn, WithInitialBufferSize(len(benchmarkOutput))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkOutput(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := Output(benchmarkFn); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkOutputReusing(b *testing.B) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := OutputReusing(stdout, stderr, benchmarkFn); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package capture
+
+import "strings"
+
+// OutputProgress captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, keeping only the
+// final state of any line rewritten using carriage returns (as for a
+// progress bar or spinner).
+//
+// Each captured line is split on "\r" and only the last non-empty
+// segment is kept, being the text that remains visible after the line
+// has been rewritten.  This is a heuristic: it assumes that each
+// segment completely overwrites the previous one.  A segment shorter
+// than the one before it would, on a terminal, leave the end of the
+// earlier segment visible; OutputProgress returns only the shorter
+// segment.  Terminal control sequences (such as "\x1b[K", to erase a
+// line) are not interpreted and are retained in the output (see
+// WithStripANSI).
+//
+// A "\r" preceding a newline is removed, as for Output.
+//
+// Error handling is identical to Output.
+//
+// Example:
+//
+//	  func TestDownload(t *testing.T) {
+//		stdout, _, _ := capture.OutputProgress(func () error {
+//		   fmt.Print("downloading  0%\r")
+//		   fmt.Print("downloading 50%\r")
+//		   fmt.Print("downloading 100%\n")
+//		   return nil
+//		})
+//
+//		// stdout: []string{"downloading 100%"}
+//	  }
+func OutputProgress(fn func() error) ([]string, []string, error) {
+	stdout, stderr, err := Output(fn)
+	return settleLines(stdout), settleLines(stderr), err
+}
+
+// settleLines replaces each of a slice of lines with its settled
+// state, as described for OutputProgress.
+func settleLines(l []string) []string {
+	for i, s := range l {
+		l[i] = settle(s)
+	}
+	return l
+}
+
+// settle returns the last non-empty "\r" separated segment of a line,
+// or "" if there are no non-empty segments.
+func settle(s string) string {
+	for {
+		i := strings.LastIndexByte(s, '\r')
+		if i < 0 {
+			return s
+		}
+		if i < len(s)-1 {
+			return s[i+1:]
+		}
+		s = s[:i]
+	}
+}
@@ -0,0 +1,81 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputProgress(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	stdout, stderr, err := OutputProgress(func() error {
+		fmt.Println("starting")
+		for _, pct := range []int{0, 25, 50, 75, 100} {
+			fmt.Printf("\rdownloading %3d%%", pct)
+		}
+		fmt.Println()
+		fmt.Print("working |\rworking /\rworking -\rdone     \r")
+		os.Stderr.WriteString("1/3\r2/3\r3/3\r\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		wanted := []string{"starting", "downloading 100%", "done     "}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		wanted := []string{"3/3"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
+
+func TestSettle(t *testing.T) {
+	// ARRANGE
+	testcases := []struct {
+		input  string
+		result string
+	}{
+		{input: "", result: ""},
+		{input: "abc", result: "abc"},
+		{input: "a\rb", result: "b"},
+		{input: "a\rb\r", result: "b"},
+		{input: "a\r\r", result: "a"},
+		{input: "\r", result: ""},
+		{input: "\rabc", result: "abc"},
+		{input: "long\rshort", result: "short"},
+	}
+	for _, tc := range testcases {
+		t.Run(fmt.Sprintf("%q", tc.input), func(t *testing.T) {
+			// ACT
+			result := settle(tc.input)
+
+			// ASSERT
+			wanted := tc.result
+			got := result
+			if wanted != got {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package capture
+
+// OutputRaw captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, except that a
+// trailing empty line is not removed from the captured lines.
+//
+// Output splits captured output on newlines and then drops a final
+// empty element, so that output terminated by a newline does not
+// result in a spurious "" line.  As a consequence, Output cannot
+// distinguish output that is terminated by a newline from output that
+// is not:
+//
+//	output          Output               OutputRaw
+//	------          ------               ---------
+//	"foo"           ["foo"]              ["foo"]
+//	"foo\n"         ["foo"]              ["foo" ""]
+//	"foo\n\n"       ["foo" ""]           ["foo" "" ""]
+//	""              nil                  nil
+//
+// OutputRaw retains the final element, so that every newline in the
+// captured output is reflected in the result.  Error handling is
+// identical to Output.
+func OutputRaw(fn func() error) ([]string, []string, error) {
+	stdout, stderr, err := output(fn)
+	return rawLines(string(stdout)), rawLines(string(stderr)), err
+}
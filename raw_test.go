@@ -0,0 +1,38 @@
+package capture
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestOutputRaw(t *testing.T) {
+	testcases := []struct {
+		name   string
+		output string
+		result []string
+	}{
+		{name: "no output", output: "", result: nil},
+		{name: "no trailing newline", output: "foo", result: []string{"foo"}},
+		{name: "trailing newline", output: "foo\n", result: []string{"foo", ""}},
+		{name: "trailing blank line", output: "foo\n\n", result: []string{"foo", "", ""}},
+		{name: "crlf", output: "foo\r\n", result: []string{"foo", ""}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ACT
+			stdout, _, err := OutputRaw(func() error { fmt.Print(tc.output); return nil })
+
+			// ASSERT
+			if err != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", err)
+			}
+
+			wanted := tc.result
+			got := stdout
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package capture
+
+import "io"
+
+// OutputReader captures the stdout and stderr output produced during
+// execution of a supplied function, returning readers from which the
+// captured output may be read as it is produced, rather than returning
+// the captured output when the function returns.
+//
+// The function is run on a separate goroutine; OutputReader returns
+// immediately.  The returned channel receives the error returned by
+// the function (joined with any ErrStdoutCapture and/or
+// ErrStderrCapture errors) once the function has returned and all
+// output has been read; the channel is then closed.  The readers
+// return io.EOF once all captured output has been read.
+//
+// The captured output is not buffered: the caller MUST read both
+// readers to completion (concurrently, or the function may block
+// writing to one stream while the caller is waiting on the other).  A
+// function writing to a stream that is not being read will block once
+// the pipe buffer is full.  The WithBlockWarning option may be used to
+// diagnose a capture that blocks for this reason.  Of the other
+// options, only WithCopyFunc and WithDrainTimeout apply; any others are
+// ignored.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, stderr, done := capture.OutputReader(func () error {
+//		   return json.NewEncoder(os.Stdout).Encode(something)
+//		})
+//		go io.Copy(io.Discard, stderr)
+//
+//		var v any
+//		if err := json.NewDecoder(stdout).Decode(&v); err != nil {
+//		   ...
+//		}
+//		_, _ = io.Copy(io.Discard, stdout)
+//
+//		fmt.Printf("error: %v", <-done)
+//	  }
+func OutputReader(fn func() error, opts ...Option) (io.Reader, io.Reader, <-chan error) {
+	cfg := newConfig(opts)
+
+	outr, outw := io.Pipe()
+	errr, errw := io.Pipe()
+	done := make(chan error, 1)
+
+	mu.spawn(func() {
+		defer close(done)
+
+		o := redirect(cfg.watch(StdoutStream, outw), cfg.watch(StderrStream, errw), cfg.copier(), cfg.drain, fn)
+		_ = outw.CloseWithError(o.stdout)
+		_ = errw.CloseWithError(o.stderr)
+
+		done <- o.join()
+	})
+
+	return outr, errr, done
+}
@@ -0,0 +1,93 @@
+package capture
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputReader(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	progress := make(chan struct{})
+
+	// ACT
+	stdout, stderr, done := OutputReader(func() error {
+		fmt.Println("line 1")
+		<-progress // wait until line 1 has been read
+		fmt.Println("line 2")
+		os.Stderr.WriteString("to stderr")
+		return fnerr
+	})
+
+	errc := make(chan []byte)
+	go func() { b, _ := io.ReadAll(stderr); errc <- b }()
+
+	scanner := bufio.NewScanner(stdout)
+	lines := []string{}
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) == 1 {
+			close(progress)
+		}
+	}
+	errout := <-errc
+	err := <-done
+
+	// ASSERT
+	t.Run("stdout streamed", func(t *testing.T) {
+		wanted := []string{"line 1", "line 2"}
+		got := lines
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stderr streamed", func(t *testing.T) {
+		wanted := "to stderr"
+		got := string(errout)
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("done closed", func(t *testing.T) {
+		if _, ok := <-done; ok {
+			t.Error("done channel not closed")
+		}
+	})
+
+	t.Run("nested in Output", func(t *testing.T) {
+		// ACT
+		var inner []byte
+		outer, _, _ := Output(func() error {
+			fmt.Println("outer")
+			stdout, stderr, done := OutputReader(func() error {
+				fmt.Println("inner")
+				return nil
+			})
+			go func() { _, _ = io.Copy(io.Discard, stderr) }()
+			inner, _ = io.ReadAll(stdout)
+			return <-done
+		})
+
+		// ASSERT
+		wanted := []string{"outer", "inner\n"}
+		got := append(outer, string(inner))
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package capture
+
+// OutputRecover captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, recovering from any
+// panic in the function.
+//
+// If the function panics, the recovered value is returned together
+// with any output captured up to the point of the panic.  If the
+// function does not panic, the returned panic value is nil.
+//
+// NOTE: Output and the other capture functions do not recover from
+// panics; streams are restored and captured output is discarded as the
+// panic propagates.
+//
+// A panic that is not recovered terminates the process; the panic
+// message and stack traces are then written by the runtime directly to
+// file descriptor 2, bypassing os.Stderr, and cannot be captured (by
+// any capture function).  A stack trace obtained when recovering from a
+// panic and written to os.Stderr (e.g. using debug.PrintStack) is
+// captured as any other output.  Output written by the runtime that
+// does not terminate the process (e.g. by the print and println
+// builtins) is written to file descriptor 2 and may be captured using
+// OutputFD.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, stderr, r, err := capture.OutputRecover(func () error {
+//		   fmt.Println("about to panic")
+//		   panic("oops")
+//		})
+//
+//		fmt.Printf("stdout: %v", stdout) // [about to panic]
+//		fmt.Printf("stderr: %v", stderr)
+//		fmt.Printf("panic: %v", r)       // oops
+//		fmt.Printf("error: %v", err)
+//	  }
+func OutputRecover(fn func() error) ([]string, []string, any, error) {
+	var r any
+	stdout, stderr, err := Output(func() error {
+		defer func() { r = recover() }()
+		return fn()
+	})
+	return stdout, stderr, r, err
+}
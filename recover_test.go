@@ -0,0 +1,131 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOutputRecover(t *testing.T) {
+	// ACT
+	stdout, stderr, r, err := OutputRecover(func() error {
+		fmt.Println("before panic")
+		os.Stderr.WriteString("also before panic")
+		panic("panic value")
+	})
+
+	// ASSERT
+	t.Run("returns panic value", func(t *testing.T) {
+		wanted := "panic value"
+		got := r
+		if wanted != got {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("no error", func(t *testing.T) {
+		got := err
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+
+	t.Run("partial output captured", func(t *testing.T) {
+		wanted := [][]string{{"before panic"}, {"also before panic"}}
+		got := [][]string{stdout, stderr}
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("when function does not panic", func(t *testing.T) {
+		// ACT
+		_, _, r, _ := OutputRecover(func() error { return nil })
+
+		// ASSERT
+		got := r
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+}
+
+func TestOutputPanic(t *testing.T) {
+	// ARRANGE
+	ogout, ogerr := os.Stdout, os.Stderr
+	goroutines := runtime.NumGoroutine()
+
+	// ACT
+	func() {
+		defer func() { _ = recover() }()
+		_, _, _ = Output(func() error {
+			fmt.Println("some output")
+			panic("panic")
+		})
+	}()
+
+	// ASSERT
+	t.Run("streams restored", func(t *testing.T) {
+		if os.Stdout != ogout || os.Stderr != ogerr {
+			t.Errorf("os.Stdout and/or os.Stderr not restored")
+		}
+	})
+
+	// goroutines may take a moment to exit after completing their work
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	remaining := runtime.NumGoroutine()
+
+	t.Run("capture goroutines completed", func(t *testing.T) {
+		wanted := goroutines
+		got := remaining
+		if got > wanted {
+			t.Errorf("\nwanted: <= %d goroutines\ngot   : %d", wanted, got)
+		}
+	})
+}
+
+func TestOutputRecoveredStackTrace(t *testing.T) {
+	// ACT
+	_, stderr, err := Output(func() error {
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					fmt.Fprintf(os.Stderr, "recovered: %v\n", r)
+					debug.PrintStack()
+				}
+			}()
+			panic("boom")
+		}()
+		wg.Wait()
+		return nil
+	})
+
+	// ASSERT
+	t.Run("returns no error", func(t *testing.T) {
+		got := err
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+
+	t.Run("stack trace captured", func(t *testing.T) {
+		got := strings.Join(stderr, "\n")
+		for _, wanted := range []string{"recovered: boom", "goroutine ", "TestOutputRecoveredStackTrace"} {
+			if !strings.Contains(got, wanted) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		}
+	})
+}
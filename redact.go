@@ -0,0 +1,45 @@
+package capture
+
+import "regexp"
+
+// redacted replaces any match of a redaction pattern.
+const redacted = "***"
+
+// redactLines replaces any match of the supplied patterns in each of a
+// slice of lines with "***".
+func redactLines(l []string, patterns []*regexp.Regexp) []string {
+	for i, s := range l {
+		for _, re := range patterns {
+			s = re.ReplaceAllLiteralString(s, redacted)
+		}
+		l[i] = s
+	}
+	return l
+}
+
+// OutputRedact captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, replacing any match
+// of the supplied patterns in the captured lines with "***".
+//
+// This prevents secrets (e.g. tokens or passwords) in captured output
+// being leaked when the output is subsequently logged (e.g. using
+// t.Log) or stored (e.g. as a golden file).  Patterns are applied, in
+// order, to each line once the output has been captured in full, so a
+// match is found even if the output was written (or read from the
+// pipe) in separate chunks.  Patterns do not match across lines.
+//
+// Error handling is identical to Output.
+//
+// Example:
+//
+//	  func TestLogin(t *testing.T) {
+//		token := regexp.MustCompile(`token=\S+`)
+//		stdout, _, _ := capture.OutputRedact([]*regexp.Regexp{token}, func () error {
+//		   return login()
+//		})
+//		t.Log(stdout) // [logged in with ***]
+//	  }
+func OutputRedact(patterns []*regexp.Regexp, fn func() error) ([]string, []string, error) {
+	stdout, stderr, err := Output(fn)
+	return redactLines(stdout, patterns), redactLines(stderr, patterns), err
+}
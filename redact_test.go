@@ -0,0 +1,55 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestOutputRedact(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	patterns := []*regexp.Regexp{
+		regexp.MustCompile(`token=\S+`),
+		regexp.MustCompile(`password: \w+`),
+	}
+
+	// ACT
+	stdout, stderr, err := OutputRedact(patterns, func() error {
+		// the secret is split across writes
+		fmt.Print("logged in with tok")
+		fmt.Print("en=abc")
+		fmt.Println("123 ok")
+		fmt.Println()
+		os.Stderr.WriteString("password: hunter2 rejected\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		wanted := []string{"logged in with *** ok", ""}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		wanted := []string{"*** rejected"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
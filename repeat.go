@@ -0,0 +1,44 @@
+package capture
+
+import "fmt"
+
+// OutputN calls a supplied function n times, capturing the stdout and
+// stderr output of each call as a single, ordered stream (as for
+// Combined).  The output of each call is returned as an element of the
+// returned slice, which may be compared to identify any variation in the
+// output of the function.
+//
+// Each call is captured separately, with os.Stdout and os.Stderr
+// restored before the next call.  If a call returns an error (or an
+// error occurs capturing its output), no further calls are made; the
+// output of the calls made (including that of the failing call) is
+// returned together with the error, wrapped with the (0-based) index
+// of the failing call, e.g. "run 3: some error".
+//
+// If n is zero (or less), the function is not called and nil is
+// returned.
+//
+// Example:
+//
+//	  func TestDeterministic(t *testing.T) {
+//		runs, err := capture.OutputN(10, func () error {
+//		   return printReport()
+//		})
+//		...
+//		for i, run := range runs[1:] {
+//		   if !slices.Equal(runs[0], run) {
+//		      t.Errorf("run %d differs: %v", i+1, run)
+//		   }
+//		}
+//	  }
+func OutputN(n int, fn func() error) ([][]string, error) {
+	var runs [][]string
+	for i := 0; i < n; i++ {
+		output, err := Combined(fn)
+		runs = append(runs, output)
+		if err != nil {
+			return runs, fmt.Errorf("run %d: %w", i, err)
+		}
+	}
+	return runs, nil
+}
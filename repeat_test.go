@@ -0,0 +1,83 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputN(t *testing.T) {
+	t.Run("when all runs succeed", func(t *testing.T) {
+		// ARRANGE
+		og := os.Stdout
+		var i int
+
+		// ACT
+		runs, err := OutputN(3, func() error {
+			if os.Stdout == og {
+				return errors.New("stdout not captured")
+			}
+			i++
+			fmt.Printf("run %d\n", i)
+			os.Stderr.WriteString("to stderr\n")
+			return nil
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		wanted := [][]string{
+			{"run 1", "to stderr"},
+			{"run 2", "to stderr"},
+			{"run 3", "to stderr"},
+		}
+		got := runs
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		if os.Stdout != og {
+			t.Error("stdout not restored")
+		}
+	})
+
+	t.Run("when a run fails", func(t *testing.T) {
+		// ARRANGE
+		fnerr := errors.New("function error")
+		var i int
+
+		// ACT
+		runs, err := OutputN(5, func() error {
+			i++
+			fmt.Printf("run %d\n", i)
+			if i == 2 {
+				return fnerr
+			}
+			return nil
+		})
+
+		// ASSERT
+		if !errors.Is(err, fnerr) {
+			t.Errorf("error:\nwanted: %v\ngot   : %v", fnerr, err)
+		}
+		if wanted, got := "run 1: function error", err.Error(); wanted != got {
+			t.Errorf("error:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		if wanted, got := [][]string{{"run 1"}, {"run 2"}}, runs; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("runs:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when n is zero", func(t *testing.T) {
+		// ACT
+		runs, err := OutputN(0, func() error { panic("called") })
+
+		// ASSERT
+		if runs != nil || err != nil {
+			t.Errorf("\nwanted: nil, nil\ngot   : %q, %v", runs, err)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package capture
+
+import "strings"
+
+// Result holds the outcome of capturing the output of a function.
+type Result struct {
+	Stdout []string // the captured stdout lines
+	Stderr []string // the captured stderr lines
+	Err    error    // any error returned by the function, joined with any capture errors
+}
+
+// StdoutString returns the captured stdout lines joined with newlines.
+func (r Result) StdoutString() string {
+	return strings.Join(r.Stdout, "\n")
+}
+
+// StderrString returns the captured stderr lines joined with newlines.
+func (r Result) StderrString() string {
+	return strings.Join(r.Stderr, "\n")
+}
+
+// HasError returns true if the function returned an error or an error
+// occurred while capturing its output.
+func (r Result) HasError() bool {
+	return r.Err != nil
+}
+
+// OutputResult captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, returning the
+// captured output and any error in a Result.
+//
+// Using a Result avoids the need to remember the order of the values
+// returned by Output and the stdout and stderr output cannot be
+// accidentally swapped at the call site.
+//
+// Example:
+//
+//	  func TestDoSomething(t *testing.T) {
+//		r := capture.OutputResult(func () error {
+//		   return doSomething()
+//		})
+//		if r.HasError() {
+//		   t.Fatalf("unexpected error: %v\nstderr: %s", r.Err, r.StderrString())
+//		}
+//		...
+//	  }
+func OutputResult(fn func() error, opts ...Option) Result {
+	// the captured output is copied when converted to lines, so pooled
+	// buffers may be used and returned to the pool once converted
+	outbuf, errbuf := getBuffer(), getBuffer()
+	defer putBuffer(outbuf)
+	defer putBuffer(errbuf)
+
+	cfg := newConfig(opts)
+	stdout, stderr, err := outputTo(outbuf, errbuf, fn, opts...)
+	return Result{
+		Stdout: splitLong(lines(string(stdout)), cfg.maxLine),
+		Stderr: splitLong(lines(string(stderr)), cfg.maxLine),
+		Err:    err,
+	}
+}
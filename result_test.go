@@ -0,0 +1,81 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputResult(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	result := OutputResult(func() error {
+		fmt.Println("stdout 1")
+		fmt.Println("stdout 2")
+		os.Stderr.WriteString("stderr\n")
+		return fnerr
+	}, WithStripANSI())
+
+	// ASSERT
+	t.Run("Err", func(t *testing.T) {
+		wanted := fnerr
+		got := result.Err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("Stdout", func(t *testing.T) {
+		wanted := []string{"stdout 1", "stdout 2"}
+		got := result.Stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("Stderr", func(t *testing.T) {
+		wanted := []string{"stderr"}
+		got := result.Stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("StdoutString", func(t *testing.T) {
+		wanted := "stdout 1\nstdout 2"
+		got := result.StdoutString()
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("StderrString", func(t *testing.T) {
+		wanted := "stderr"
+		got := result.StderrString()
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("HasError", func(t *testing.T) {
+		t.Run("when there is an error", func(t *testing.T) {
+			wanted := true
+			got := result.HasError()
+			if wanted != got {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+
+		t.Run("when there is no error", func(t *testing.T) {
+			wanted := false
+			got := Result{}.HasError()
+			if wanted != got {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+	})
+}
@@ -0,0 +1,175 @@
+//go:build !race
+
+// The writers in these tests deliberately resolve os.Stdout (or
+// os.Stderr) on every write while a capture is being closed, as
+// (unsynchronized) background goroutines in code under test do.  That
+// is a data race by definition, so the tests are not run with the race
+// detector.
+
+package capture
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCaptureShutdown(t *testing.T) {
+	testcases := []struct {
+		name    string
+		file    func() *os.File
+		capture func(fn func() error) []string
+	}{
+		{name: "Output",
+			file:    func() *os.File { return os.Stdout },
+			capture: func(fn func() error) []string { out, _, _ := Output(fn); return out },
+		},
+		{name: "Combined",
+			file:    func() *os.File { return os.Stderr },
+			capture: func(fn func() error) []string { out, _ := Combined(fn); return out },
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ARRANGE
+			const iterations = 20
+			var (
+				written  int
+				inner    int
+				werrs    []error
+				panicked any
+			)
+
+			// ACT
+			stdout, stderr, err := Output(func() error {
+				for i := 0; i < iterations; i++ {
+					stop := make(chan struct{})
+					wg := &sync.WaitGroup{}
+
+					out := tc.capture(func() error {
+						wg.Add(1)
+						go func() {
+							defer wg.Done()
+							defer func() { panicked = recover() }()
+							for {
+								select {
+								case <-stop:
+									return
+								default:
+								}
+								if _, err := tc.file().WriteString("x"); err != nil {
+									werrs = append(werrs, err)
+									continue
+								}
+								written++
+								runtime.Gosched()
+							}
+						}()
+						time.Sleep(time.Millisecond)
+						return nil
+					})
+					inner += len(strings.Join(out, ""))
+
+					close(stop)
+					wg.Wait()
+				}
+				return nil
+			})
+
+			// ASSERT
+			t.Run("returns no error", func(t *testing.T) {
+				got := err
+				if got != nil {
+					t.Errorf("\nwanted: nil\ngot   : %v", got)
+				}
+			})
+
+			t.Run("no panic", func(t *testing.T) {
+				got := panicked
+				if got != nil {
+					t.Errorf("\nwanted: nil\ngot   : %v", got)
+				}
+			})
+
+			t.Run("write errors", func(t *testing.T) {
+				// a write may fail only if it was made using the pipe of the
+				// inner capture, resolved from the file before the capture was
+				// closed
+				for _, err := range werrs {
+					if !errors.Is(err, os.ErrClosed) {
+						t.Errorf("\nwanted: %v\ngot   : %v", os.ErrClosed, err)
+					}
+				}
+				if len(werrs) > iterations {
+					t.Errorf("\nwanted: <= %d errors\ngot   : %d", iterations, len(werrs))
+				}
+			})
+
+			t.Run("all successful writes captured", func(t *testing.T) {
+				wanted := written
+				got := inner + len(strings.Join(stdout, "")) + len(strings.Join(stderr, ""))
+				if wanted != got {
+					t.Errorf("\nwanted: %d bytes\ngot   : %d bytes (inner: %d)", wanted, got, inner)
+				}
+			})
+		})
+	}
+}
+
+func TestOutputTimedBackgroundWriter(t *testing.T) {
+	// ARRANGE
+	const iterations = 20
+	stop := make(chan struct{})
+	wg := &sync.WaitGroup{}
+
+	// creating the stderr pipe is delayed, so that lines written to the
+	// stdout pipe are captured before the function is called
+	og := pipeFn
+	defer func() { pipeFn = og }()
+	var pipes int
+
+	// ACT
+	_, _, err := Output(func() error {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				_, _ = os.Stdout.WriteString("background\n")
+				runtime.Gosched()
+			}
+		}()
+		defer wg.Wait()
+		defer close(stop)
+
+		pipeFn = func() (*os.File, *os.File, error) {
+			if pipes++; pipes%2 == 0 {
+				time.Sleep(5 * time.Millisecond)
+			}
+			return og()
+		}
+
+		// a line written by the background goroutine after the capture is
+		// started, but before the function is called, must not panic
+		for i := 0; i < iterations; i++ {
+			if _, _, err := OutputTimed(func() error { return nil }); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	// ASSERT
+	got := err
+	if got != nil {
+		t.Errorf("\nwanted: nil\ngot   : %v", got)
+	}
+}
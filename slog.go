@@ -0,0 +1,46 @@
+package capture
+
+import (
+	"log"
+	"log/slog"
+	"os"
+)
+
+// OutputWithSlog captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, additionally
+// capturing output written using the default slog logger.
+//
+// For the duration of the call the default slog logger is replaced with
+// a logger using a slog.TextHandler writing to the captured stderr.  The
+// previous default logger is restored when the function returns (or
+// panics), even if the function itself calls slog.SetDefault.
+//
+// Since slog.SetDefault also redirects the output of the standard log
+// package, the output and flags of the standard logger are also saved
+// and restored.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		_, stderr, _ := capture.OutputWithSlog(func () error {
+//		   slog.Info("some log output", "key", "value")
+//		   return nil
+//		})
+//
+//		fmt.Printf("stderr: %v", stderr) // [time=... level=INFO msg="some log output" key=value]
+//	  }
+func OutputWithSlog(fn func() error) ([]string, []string, error) {
+	return Output(func() error {
+		og := slog.Default()
+		ogw, ogf := log.Writer(), log.Flags()
+		defer func() {
+			slog.SetDefault(og)
+			log.SetOutput(ogw)
+			log.SetFlags(ogf)
+		}()
+
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+		return fn()
+	})
+}
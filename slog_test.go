@@ -0,0 +1,59 @@
+package capture
+
+import (
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestOutputWithSlog(t *testing.T) {
+	// ARRANGE
+	og := slog.Default()
+	ogw := log.Writer()
+
+	// ACT
+	_, stderr, _ := OutputWithSlog(func() error {
+		slog.Info("log output", "key", "value")
+		return nil
+	})
+
+	// ASSERT
+	t.Run("log output captured", func(t *testing.T) {
+		if len(stderr) != 1 || !strings.Contains(stderr[0], `level=INFO msg="log output" key=value`) {
+			t.Errorf("\nwanted: [time=... level=INFO msg=\"log output\" key=value]\ngot   : %v", stderr)
+		}
+	})
+
+	t.Run("default logger restored", func(t *testing.T) {
+		wanted := og
+		got := slog.Default()
+		if wanted != got {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("standard logger output restored", func(t *testing.T) {
+		wanted := ogw
+		got := log.Writer()
+		if wanted != got {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("when function replaces the default logger", func(t *testing.T) {
+		// ACT
+		_, _, _ = OutputWithSlog(func() error {
+			slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			return nil
+		})
+
+		// ASSERT
+		wanted := og
+		got := slog.Default()
+		if wanted != got {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package capture
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// OutputANSISnapshot captures the stdout and stderr output produced
+// during execution of a supplied function as a single, ordered stream
+// (as for Combined), returning a representation of the output suitable
+// for comparison with a golden (snapshot) file.
+//
+// This is the inverse of OutputPlain: all ANSI escape sequences and
+// other control characters are preserved.  The output is captured
+// verbatim, before any splitting into lines, so a sequence split across
+// two (or more) writes is reassembled exactly as it would be received
+// by a terminal, and sequences containing newlines are unaffected.
+//
+// To make the snapshot readable (and diffable) as text, control bytes
+// are rendered as escapes:
+//
+//	byte            rendered as
+//	----            -----------
+//	ESC (0x1b)      \x1b
+//	CR (0x0d)       \r
+//	other C0, DEL   \xNN
+//	backslash       \\
+//
+// Newlines and tabs are retained as-is and all other bytes (including
+// any UTF-8 encoded characters) are unchanged.
+//
+// Error handling is identical to Combined.  If there is no output, the
+// returned string is empty.
+//
+// Example:
+//
+//	  func TestProgressBar(t *testing.T) {
+//		got, err := capture.OutputANSISnapshot(func () error {
+//		   return renderProgress(50)
+//		})
+//		...
+//		// got: `\x1b[32m=====\x1b[0m     50%\r`
+//	  }
+func OutputANSISnapshot(fn func() error) (string, error) {
+	output, err := combined(io.Copy, fn)
+	return snapshot(output), err
+}
+
+// snapshot renders captured output for comparison with a golden file,
+// as described for OutputANSISnapshot.
+func snapshot(b []byte) string {
+	sb := &strings.Builder{}
+	sb.Grow(len(b))
+
+	for _, c := range b {
+		switch {
+		case c == '\n' || c == '\t':
+			sb.WriteByte(c)
+		case c == '\\':
+			sb.WriteString(`\\`)
+		case c == '\r':
+			sb.WriteString(`\r`)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(sb, `\x%02x`, c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String()
+}
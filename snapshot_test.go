@@ -0,0 +1,54 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestOutputANSISnapshot(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	result, err := OutputANSISnapshot(func() error {
+		// an SGR sequence split across two writes
+		os.Stdout.WriteString("\x1b[3")
+		os.Stdout.WriteString("1mred\x1b[0m\n")
+		// an OSC sequence (hyperlink) containing a newline
+		os.Stderr.WriteString("\x1b]8;;file:///a\nb\x07link\x1b]8;;\x07\n")
+		fmt.Print("50%\r100%\t\\done")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		wanted := `\x1b[31mred\x1b[0m` + "\n" +
+			`\x1b]8;;file:///a` + "\n" + `b\x07link\x1b]8;;\x07` + "\n" +
+			`50%\r100%` + "\t" + `\\done`
+		got := result
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when no output is produced", func(t *testing.T) {
+		// ACT
+		result, _ := OutputANSISnapshot(func() error { return nil })
+
+		// ASSERT
+		got := result
+		if got != "" {
+			t.Errorf("\nwanted: \"\"\ngot   : %q", got)
+		}
+	})
+}
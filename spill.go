@@ -0,0 +1,69 @@
+package capture
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// spillWriter is an io.Writer that writes to a buffer until a threshold
+// number of bytes have been written, writing any further bytes to a
+// temporary file.  The content of the file is read into the buffer by
+// complete.
+type spillWriter struct {
+	buf       *bytes.Buffer
+	threshold int64
+	buffered  int64 // bytes written to buf
+	spilled   int64 // bytes written to f
+	f         *os.File
+}
+
+// Write implements io.Writer.
+func (sw *spillWriter) Write(p []byte) (int, error) {
+	if sw.f == nil && sw.buffered+int64(len(p)) <= sw.threshold {
+		n, err := sw.buf.Write(p)
+		sw.buffered += int64(n)
+		return n, err
+	}
+
+	if sw.f == nil {
+		f, err := os.CreateTemp("", "capture-*")
+		if err != nil {
+			return 0, err
+		}
+		sw.f = f
+	}
+
+	n, err := sw.f.Write(p)
+	sw.spilled += int64(n)
+	return n, err
+}
+
+// complete reads any bytes written to the temporary file into the
+// buffer and removes the file.  If no bytes were spilled, complete has
+// no effect.
+func (sw *spillWriter) complete() error {
+	if sw.f == nil {
+		return nil
+	}
+	defer sw.discard()
+
+	if _, err := sw.f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	sw.buf.Grow(int(sw.spilled))
+	_, err := sw.buf.ReadFrom(sw.f)
+	return err
+}
+
+// discard closes and removes any temporary file, discarding any bytes
+// written to it.  If there is no temporary file, discard has no effect.
+func (sw *spillWriter) discard() {
+	if sw.f == nil {
+		return
+	}
+	f := sw.f
+	sw.f = nil
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+}
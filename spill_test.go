@@ -0,0 +1,179 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setTempDir sets the directory returned by os.TempDir for the duration
+// of a test.
+func setTempDir(t *testing.T, dir string) {
+	for _, v := range []string{"TMPDIR", "TMP", "TEMP"} {
+		t.Setenv(v, dir)
+	}
+}
+
+func TestSpillWriter(t *testing.T) {
+	// ARRANGE
+	setTempDir(t, t.TempDir())
+	buf := &bytes.Buffer{}
+	sut := &spillWriter{buf: buf, threshold: 8}
+
+	// ACT
+	_, _ = sut.Write([]byte("abcd"))
+	_, _ = sut.Write([]byte("efgh"))
+	buffered := buf.String()
+	_, _ = sut.Write([]byte("ijkl"))
+	_, _ = sut.Write([]byte("mn"))
+	spilled := sut.f != nil
+	err := sut.complete()
+
+	// ASSERT
+	t.Run("buffers up to threshold", func(t *testing.T) {
+		wanted := "abcdefgh"
+		got := buffered
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("spills beyond threshold", func(t *testing.T) {
+		if !spilled {
+			t.Error("no temporary file created")
+		}
+	})
+
+	t.Run("completes buffer", func(t *testing.T) {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		wanted := "abcdefghijklmn"
+		got := buf.String()
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("removes temporary file", func(t *testing.T) {
+		files, _ := os.ReadDir(os.TempDir())
+		if len(files) > 0 {
+			t.Errorf("\nwanted: <none>\ngot   : %v", files)
+		}
+	})
+}
+
+func TestWithSpillThreshold(t *testing.T) {
+	t.Run("with large output", func(t *testing.T) {
+		// ARRANGE
+		tmp := t.TempDir()
+		setTempDir(t, tmp)
+		line := strings.Repeat("x", 1023)
+
+		// ACT
+		stdout, stderr, err := Output(func() error {
+			for i := 0; i < 4096; i++ { // 4 MiB
+				os.Stdout.WriteString(line + "\n")
+			}
+			os.Stderr.WriteString("to stderr\n")
+			return nil
+		}, WithSpillThreshold(64<<10))
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if wanted, got := 4096, len(stdout); wanted != got {
+			t.Errorf("stdout lines:\nwanted: %d\ngot   : %d", wanted, got)
+		}
+		for i, got := range stdout {
+			if got != line {
+				t.Errorf("stdout line %d:\nwanted: %d x's\ngot   : %q", i, len(line), got)
+				break
+			}
+		}
+		if wanted, got := []string{"to stderr"}, stderr; len(got) != 1 || got[0] != wanted[0] {
+			t.Errorf("stderr:\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		if files, _ := os.ReadDir(tmp); len(files) > 0 {
+			t.Errorf("temporary files not removed: %v", files)
+		}
+	})
+
+	t.Run("when function panics", func(t *testing.T) {
+		// ARRANGE
+		tmp := t.TempDir()
+		setTempDir(t, tmp)
+
+		// ACT
+		func() {
+			defer func() { _ = recover() }()
+			_, _, _ = Output(func() error {
+				os.Stdout.WriteString("more than the threshold\n")
+				os.Stderr.WriteString("more than the threshold\n")
+				panic("function panicked")
+			}, WithSpillThreshold(10))
+		}()
+
+		// ASSERT
+		if files, _ := os.ReadDir(tmp); len(files) > 0 {
+			t.Errorf("temporary files not removed: %v", files)
+		}
+	})
+
+	t.Run("when temporary file cannot be created", func(t *testing.T) {
+		// ARRANGE
+		setTempDir(t, filepath.Join(t.TempDir(), "nonexistent"))
+
+		// ACT
+		stdout, stderr, err := Output(func() error {
+			os.Stdout.WriteString("more than the threshold\n")
+			os.Stderr.WriteString("err\n") // within the threshold
+			return nil
+		}, WithSpillThreshold(4))
+
+		// ASSERT
+		if wanted, got := ErrStdoutCapture, err; !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+		if stdout != nil {
+			t.Errorf("stdout:\nwanted: nil\ngot   : %q", stdout)
+		}
+		if wanted, got := []string{"err"}, stderr; errors.Is(err, ErrStderrCapture) || len(got) != 1 || got[0] != wanted[0] {
+			t.Errorf("stderr:\nwanted: %q\ngot   : %q (%v)", wanted, got, err)
+		}
+	})
+}
+
+// benchmarkLargeOutput writes 16 MiB to stdout.
+func benchmarkLargeOutput() error {
+	b := bytes.Repeat([]byte(strings.Repeat("x", 1023)+"\n"), 1024)
+	for i := 0; i < 16; i++ {
+		if _, err := os.Stdout.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func BenchmarkOutputLarge(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := output(benchmarkLargeOutput); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkOutputLargeWithSpill(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := output(benchmarkLargeOutput, WithSpillThreshold(1<<20)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
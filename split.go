@@ -0,0 +1,95 @@
+package capture
+
+import "strings"
+
+// OutputSplit captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, splitting the
+// captured output on the specified separator byte rather than on
+// newlines.
+//
+// This allows capture of NUL-delimited output (as produced by tools
+// such as `find -print0`) or output using any other single-byte
+// delimiter.  The conventions of Output apply relative to the
+// separator: if there is no output the result is nil, and a trailing
+// empty element (resulting from output terminated by the separator) is
+// dropped.
+//
+// If the separator is '\n', the result is identical to Output (i.e.
+// any "\r" preceding a newline is also removed).  With any other
+// separator, the content between separators is returned verbatim.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, _, err := capture.OutputSplit(0, func () error {
+//		   fmt.Print("file1\x00file2\x00")
+//		   return nil
+//		})
+//
+//		fmt.Printf("stdout: %q", stdout) // ["file1" "file2"]
+//		fmt.Printf("error: %v", err)
+//	  }
+func OutputSplit(sep byte, fn func() error) ([]string, []string, error) {
+	stdout, stderr, err := output(fn)
+	return split(string(stdout), sep), split(string(stderr), sep), err
+}
+
+// split splits captured output on a separator, as described for
+// OutputSplit.
+func split(s string, sep byte) []string {
+	if sep == '\n' {
+		return lines(s)
+	}
+	return splitString(s, string(sep))
+}
+
+// OutputSplitString captures the stdout and stderr output produced
+// during execution of a supplied function, as for OutputSplit,
+// splitting the captured output on the specified separator string
+// rather than on a single byte.
+//
+// This allows capture of output using a multi-byte record separator,
+// such as "---\n" separating YAML documents.  The conventions of
+// OutputSplit apply relative to the separator: if there is no output
+// the result is nil, and a trailing empty element (resulting from
+// output terminated by the separator) is dropped.
+//
+// If the separator is "\n", the result is identical to Output.  With
+// any other separator, the content between separators is returned
+// verbatim.  If the separator is "", the output of each stream is
+// returned as a single element.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, _, err := capture.OutputSplitString("---\n", func () error {
+//		   fmt.Print("a: 1\n---\nb: 2\n")
+//		   return nil
+//		})
+//
+//		fmt.Printf("stdout: %q", stdout) // ["a: 1\n" "b: 2\n"]
+//		fmt.Printf("error: %v", err)
+//	  }
+func OutputSplitString(sep string, fn func() error) ([]string, []string, error) {
+	stdout, stderr, err := output(fn)
+	return splitString(string(stdout), sep), splitString(string(stderr), sep), err
+}
+
+// splitString splits captured output on a separator string, as
+// described for OutputSplitString.
+func splitString(s, sep string) []string {
+	switch {
+	case sep == "\n":
+		return lines(s)
+	case s == "":
+		return nil
+	case sep == "":
+		return []string{s}
+	}
+
+	l := strings.Split(s, sep)
+	if l[len(l)-1] == "" {
+		l = l[:len(l)-1]
+	}
+	return l
+}
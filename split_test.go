@@ -0,0 +1,147 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputSplit(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	stdout, stderr, err := OutputSplit(0, func() error {
+		fmt.Print("file 1\x00file\n2\x00")
+		os.Stderr.WriteString("a\x00\x00b")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		wanted := []string{"file 1", "file\n2"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		wanted := []string{"a", "", "b"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
+
+func TestSplit(t *testing.T) {
+	// ARRANGE
+	testcases := []struct {
+		name   string
+		input  string
+		sep    byte
+		result []string
+	}{
+		{name: "empty", input: "", sep: ',', result: nil},
+		{name: "single element", input: "a", sep: ',', result: []string{"a"}},
+		{name: "terminated", input: "a,b,", sep: ',', result: []string{"a", "b"}},
+		{name: "only separator", input: ",", sep: ',', result: []string{""}},
+		{name: "carriage return retained", input: "a\r,b", sep: ',', result: []string{"a\r", "b"}},
+		{name: "newline separator", input: "a\r\nb\n", sep: '\n', result: []string{"a", "b"}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ACT
+			result := split(tc.input, tc.sep)
+
+			// ASSERT
+			wanted := tc.result
+			got := result
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	}
+}
+
+func TestOutputSplitString(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	stdout, stderr, err := OutputSplitString("---\n", func() error {
+		fmt.Print("a: 1\n---\nb: 2\n---\n")
+		os.Stderr.WriteString("--\n---\n---\n-")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		wanted := []string{"a: 1\n", "b: 2\n"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		wanted := []string{"--\n", "", "-"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
+
+func TestSplitString(t *testing.T) {
+	// ARRANGE
+	testcases := []struct {
+		name   string
+		input  string
+		sep    string
+		result []string
+	}{
+		{name: "empty", input: "", sep: "::", result: nil},
+		{name: "single element", input: "a", sep: "::", result: []string{"a"}},
+		{name: "terminated", input: "a::b::", sep: "::", result: []string{"a", "b"}},
+		{name: "only separator", input: "::", sep: "::", result: []string{""}},
+		{name: "partial separator", input: "a:b::c:", sep: "::", result: []string{"a:b", "c:"}},
+		{name: "overlapping separator", input: "a:::b", sep: "::", result: []string{"a", ":b"}},
+		{name: "single byte separator", input: "a,b,", sep: ",", result: []string{"a", "b"}},
+		{name: "newline separator", input: "a\r\nb\n", sep: "\n", result: []string{"a", "b"}},
+		{name: "empty separator", input: "a\nb", sep: "", result: []string{"a\nb"}},
+		{name: "empty separator and input", input: "", sep: "", result: nil},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ACT
+			result := splitString(tc.input, tc.sep)
+
+			// ASSERT
+			wanted := tc.result
+			got := result
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	}
+}
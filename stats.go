@@ -0,0 +1,78 @@
+package capture
+
+import "bytes"
+
+// Stats holds the volume of output captured for each stream.
+type Stats struct {
+	StdoutBytes int64 // the number of bytes written to stdout
+	StderrBytes int64 // the number of bytes written to stderr
+	StdoutLines int   // the number of lines written to stdout
+	StderrLines int   // the number of lines written to stderr
+}
+
+// counter is a writer that counts the bytes and lines written to it.
+type counter struct {
+	bytes int64
+	nl    int
+	last  byte
+}
+
+// Write implements io.Writer.
+func (c *counter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		c.bytes += int64(len(p))
+		c.nl += bytes.Count(p, []byte("\n"))
+		c.last = p[len(p)-1]
+	}
+	return len(p), nil
+}
+
+// lines returns the number of lines counted, consistent with the number
+// of lines returned by Output: a final line that is not terminated by a
+// newline is counted.
+func (c *counter) lines() int {
+	if c.bytes > 0 && c.last != '\n' {
+		return c.nl + 1
+	}
+	return c.nl
+}
+
+// OutputStats captures the stdout and stderr output produced during
+// execution of a supplied function, returning the number of bytes and
+// lines written to each stream rather than the output itself.
+//
+// The output is counted as it is captured and is not retained, so the
+// memory used is constant regardless of the volume of output.  Lines
+// are counted consistently with Output, i.e. StdoutLines is the same as
+// the length of the stdout lines that Output would return.
+//
+// Error handling is as for Output; if ErrStdoutCapture or
+// ErrStderrCapture is returned, the counts for the stream are zero.
+//
+// Of the available options, only WithCopyFunc and WithReadChunkSize
+// apply to OutputStats; any other options are ignored.
+//
+// Example:
+//
+//	  func TestLogVolume(t *testing.T) {
+//		stats, err := capture.OutputStats(func () error {
+//		   return doSomething()
+//		})
+//		if stats.StderrLines > 10 {
+//		   t.Errorf("too much logging: %d lines", stats.StderrLines)
+//		}
+//	  }
+func OutputStats(fn func() error, opts ...Option) (Stats, error) {
+	stdout, stderr := &counter{}, &counter{}
+
+	o := redirect(stdout, stderr, newConfig(opts).copier(), 0, fn)
+
+	s := Stats{}
+	if o.stdout == nil {
+		s.StdoutBytes, s.StdoutLines = stdout.bytes, stdout.lines()
+	}
+	if o.stderr == nil {
+		s.StderrBytes, s.StderrLines = stderr.bytes, stderr.lines()
+	}
+	return s, o.join()
+}
@@ -0,0 +1,79 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestOutputStats(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	stats, err := OutputStats(func() error {
+		for i := 0; i < 1000; i++ {
+			fmt.Println("0123456789")
+		}
+		os.Stderr.WriteString("line 1\nline 2")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		wanted := Stats{StdoutBytes: 11000, StdoutLines: 1000, StderrBytes: 13, StderrLines: 2}
+		got := stats
+		if wanted != got {
+			t.Errorf("\nwanted: %+v\ngot   : %+v", wanted, got)
+		}
+	})
+
+	t.Run("when copying fails", func(t *testing.T) {
+		// ARRANGE
+		cp := func(dst io.Writer, src io.Reader) (int64, error) {
+			_, _ = io.Copy(dst, src)
+			return 0, errors.New("copy error")
+		}
+
+		// ACT
+		stats, err := OutputStats(func() error { fmt.Println("output"); return nil }, WithCopyFunc(cp))
+
+		// ASSERT
+		if !errors.Is(err, ErrStdoutCapture) {
+			t.Errorf("\nwanted: %v\ngot   : %v", ErrStdoutCapture, err)
+		}
+		if got := stats; got != (Stats{}) {
+			t.Errorf("\nwanted: %+v\ngot   : %+v", Stats{}, got)
+		}
+	})
+}
+
+func TestCounterLines(t *testing.T) {
+	// ARRANGE
+	testcases := []string{"", "a", "a\n", "a\nb", "a\nb\n", "\n\n", "a\r\nb\r\n"}
+	for _, tc := range testcases {
+		t.Run(fmt.Sprintf("%q", tc), func(t *testing.T) {
+			c := &counter{}
+
+			// ACT
+			_, _ = c.Write([]byte(tc))
+
+			// ASSERT
+			wanted := len(lines(tc))
+			got := c.lines()
+			if wanted != got {
+				t.Errorf("\nwanted: %d\ngot   : %d", wanted, got)
+			}
+		})
+	}
+}
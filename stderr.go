@@ -0,0 +1,34 @@
+package capture
+
+import "os"
+
+// Stderr captures the stderr output produced during execution of
+// a supplied function.  Unlike Output, os.Stdout is not redirected;
+// stdout remains connected to the original os.Stdout (e.g. the real
+// terminal) for the duration of the call, so any stdout output is
+// written as normal and may be consumed live.
+//
+// If the supplied function returns an error, the error is returned
+// together with any captured output.
+//
+// If an error occurs while capturing the output, ErrStderrCapture is
+// returned (wrapped with any error returned from the supplied function)
+// and any captured output is discarded.
+//
+// Of the available options, only WithCopyFunc and WithReadChunkSize
+// apply to Stderr; any other options are ignored.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stderr, err := capture.Stderr(func () error {
+//		   return doSomething()
+//		})
+//
+//		fmt.Printf("stderr: %v", stderr)
+//		fmt.Printf("error: %v", err)
+//	  }
+func Stderr(fn func() error, opts ...Option) ([]string, error) {
+	s, err := captureFile(&os.Stderr, ErrStderrCapture, newConfig(opts).copier(), fn)
+	return lines(string(s)), err
+}
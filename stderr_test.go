@@ -0,0 +1,86 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestStderr(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	stdout := os.Stdout
+
+	// ACT
+	var stdoutInFn *os.File
+	stderr, err := Stderr(func() error {
+		stdoutInFn = os.Stdout
+		os.Stderr.WriteString("to stderr (1)\n")
+		os.Stderr.WriteString("to stderr (2)")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stderr captured", func(t *testing.T) {
+		wanted := []string{"to stderr (1)", "to stderr (2)"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stdout not redirected", func(t *testing.T) {
+		wanted := stdout
+		got := stdoutInFn
+		if wanted != got {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("when no output is produced", func(t *testing.T) {
+		// ACT
+		stderr, _ := Stderr(func() error { return nil })
+
+		// ASSERT
+		got := stderr
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+
+	t.Run("when error copying captured buffer", func(t *testing.T) {
+		// ARRANGE
+		cpyerr := fmt.Errorf("copy error")
+		cp := func(dst io.Writer, src io.Reader) (int64, error) { _, _ = io.Copy(dst, src); return 0, cpyerr }
+
+		// ACT
+		stderr, err := Stderr(func() error { os.Stderr.WriteString("some output"); return nil }, WithCopyFunc(cp))
+
+		// ASSERT
+		t.Run("error", func(t *testing.T) {
+			wanted := ErrStderrCapture
+			got := err
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("stderr is nil", func(t *testing.T) {
+			got := stderr
+			if got != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", got)
+			}
+		})
+	})
+}
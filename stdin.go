@@ -0,0 +1,56 @@
+package capture
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// stdin returns a function that replaces os.Stdin with a pipe supplying
+// the content of a reader for the duration of a call to a supplied
+// function, restoring the original os.Stdin when the function returns.
+//
+// Input is written to the pipe from a separate goroutine, so input
+// larger than the pipe buffer does not block.  The write end of the
+// pipe is closed once all input has been written, so that the function
+// reads io.EOF once it has consumed the input.  Any input not consumed
+// by the function is discarded.
+//
+// If the cancel channel is closed when the function returns, the
+// function is assumed to have been abandoned (by OutputContext) and may
+// still be reading from the pipe.  The write end of the pipe is then
+// closed, so that the function reads io.EOF rather than blocking while
+// waiting for further input, and the read end is left open for the
+// function to read from (it is closed once no longer referenced).
+//
+// The returned function must be called while the capture lock is held.
+func stdin(input io.Reader, cancel <-chan struct{}, fn func() error) func() error {
+	return func() error {
+		r, w, err := pipeFn()
+		if err != nil {
+			return fmt.Errorf("stdin: %w: %w", ErrPipeCreate, err)
+		}
+
+		var once sync.Once
+		closew := func() { once.Do(func() { _ = w.Close() }) }
+
+		go func() {
+			defer closew()
+			_, _ = io.Copy(w, input)
+		}()
+
+		rd := install(&os.Stdin, r)
+		defer func() {
+			rd.restore()
+			select {
+			case <-cancel:
+				closew()
+			default:
+				_ = r.Close() // unblocks the writer if input was not consumed
+			}
+		}()
+
+		return fn()
+	}
+}
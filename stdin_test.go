@@ -0,0 +1,117 @@
+package capture
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithStdin(t *testing.T) {
+	// ARRANGE
+	ogin := os.Stdin
+
+	// ACT
+	stdout, _, err := Output(func() error {
+		fmt.Print("name? ")
+		name, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			return err
+		}
+		fmt.Printf("hello %s", name)
+		return nil
+	}, WithStdin("Alice\n"))
+
+	// ASSERT
+	t.Run("no error", func(t *testing.T) {
+		got := err
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+
+	t.Run("input read", func(t *testing.T) {
+		wanted := []string{"name? hello Alice"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stdin restored", func(t *testing.T) {
+		wanted := ogin
+		got := os.Stdin
+		if wanted != got {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("reads EOF after input", func(t *testing.T) {
+		// ARRANGE
+		large := strings.Repeat("x", 256*1024) // larger than a typical pipe buffer
+
+		// ACT
+		var input []byte
+		_, _, err := Output(func() error {
+			var err error
+			input, err = io.ReadAll(os.Stdin)
+			return err
+		}, WithStdin(large))
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", err)
+		}
+		if string(input) != large {
+			t.Errorf("\nwanted: %d bytes\ngot   : %d bytes", len(large), len(input))
+		}
+	})
+
+	t.Run("when input is not consumed", func(t *testing.T) {
+		// ACT
+		_, _, err := Output(func() error { return nil }, WithStdin(strings.Repeat("x", 256*1024)))
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", err)
+		}
+	})
+}
+
+func TestWithStdinReader(t *testing.T) {
+	// ARRANGE
+	pr, pw := io.Pipe()
+	prompted := make(chan struct{})
+	go func() {
+		defer pw.Close()
+		<-prompted
+		_, _ = io.WriteString(pw, "Alice\n")
+	}()
+
+	// ACT
+	stdout, _, err := Output(func() error {
+		in := bufio.NewReader(os.Stdin)
+		fmt.Println("name?")
+		close(prompted)
+		name, err := in.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		fmt.Printf("hello %s", name)
+		return nil
+	}, WithStdinReader(pr))
+
+	// ASSERT
+	if err != nil {
+		t.Errorf("\nwanted: nil\ngot   : %v", err)
+	}
+
+	wanted := []string{"name?", "hello Alice"}
+	got := stdout
+	if !reflect.DeepEqual(wanted, got) {
+		t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+	}
+}
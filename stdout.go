@@ -0,0 +1,32 @@
+package capture
+
+import "os"
+
+// Stdout captures the stdout output produced during execution of
+// a supplied function.  Unlike Output, os.Stderr is not redirected;
+// any stderr output is written to the original os.Stderr as normal.
+//
+// If the supplied function returns an error, the error is returned
+// together with any captured output.
+//
+// If an error occurs while capturing the output, ErrStdoutCapture is
+// returned (wrapped with any error returned from the supplied function)
+// and any captured output is discarded.
+//
+// Of the available options, only WithCopyFunc and WithReadChunkSize
+// apply to Stdout; any other options are ignored.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, err := capture.Stdout(func () error {
+//		   return doSomething()
+//		})
+//
+//		fmt.Printf("stdout: %v", stdout)
+//		fmt.Printf("error: %v", err)
+//	  }
+func Stdout(fn func() error, opts ...Option) ([]string, error) {
+	s, err := captureFile(&os.Stdout, ErrStdoutCapture, newConfig(opts).copier(), fn)
+	return lines(string(s)), err
+}
@@ -0,0 +1,86 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestStdout(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	stderr := os.Stderr
+
+	// ACT
+	var stderrInFn *os.File
+	stdout, err := Stdout(func() error {
+		stderrInFn = os.Stderr
+		fmt.Println("to stdout (1)")
+		fmt.Println("to stdout (2)")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout captured", func(t *testing.T) {
+		wanted := []string{"to stdout (1)", "to stdout (2)"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stderr not redirected", func(t *testing.T) {
+		wanted := stderr
+		got := stderrInFn
+		if wanted != got {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("when no output is produced", func(t *testing.T) {
+		// ACT
+		stdout, _ := Stdout(func() error { return nil })
+
+		// ASSERT
+		got := stdout
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+
+	t.Run("when error copying captured buffer", func(t *testing.T) {
+		// ARRANGE
+		cpyerr := fmt.Errorf("copy error")
+		cp := func(dst io.Writer, src io.Reader) (int64, error) { _, _ = io.Copy(dst, src); return 0, cpyerr }
+
+		// ACT
+		stdout, err := Stdout(func() error { fmt.Println("some output"); return nil }, WithCopyFunc(cp))
+
+		// ASSERT
+		t.Run("error", func(t *testing.T) {
+			wanted := ErrStdoutCapture
+			got := err
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("stdout is nil", func(t *testing.T) {
+			got := stdout
+			if got != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", got)
+			}
+		})
+	})
+}
@@ -0,0 +1,115 @@
+package capture
+
+import (
+	"bytes"
+	"sync"
+)
+
+// Stream identifies a captured output stream.
+type Stream int
+
+const (
+	StdoutStream Stream = iota // identifies stdout
+	StderrStream               // identifies stderr
+)
+
+// String implements fmt.Stringer.
+func (s Stream) String() string {
+	switch s {
+	case StdoutStream:
+		return "stdout"
+	case StderrStream:
+		return "stderr"
+	}
+	return "unknown"
+}
+
+// lineWriter is an io.Writer that calls a function for each complete
+// line written to it.  Content written after the final newline is held
+// until further content completes the line or the writer is flushed.
+//
+// Lines passed to the function do not include the terminating newline
+// (or any "\r" preceding it).
+type lineWriter struct {
+	buf []byte
+	fn  func(string)
+}
+
+// Write implements io.Writer.
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.buf = append(lw.buf, p...)
+	for {
+		i := bytes.IndexByte(lw.buf, '\n')
+		if i < 0 {
+			break
+		}
+		lw.fn(string(bytes.TrimSuffix(lw.buf[:i], []byte("\r"))))
+		lw.buf = lw.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush calls the function with any incomplete final line.
+func (lw *lineWriter) flush() {
+	if len(lw.buf) > 0 {
+		lw.fn(string(lw.buf))
+		lw.buf = nil
+	}
+}
+
+// OutputStream captures the stdout and stderr output produced during
+// execution of a supplied function, calling a supplied function for
+// each line of output as it is captured rather than returning the
+// captured output.  No captured output is retained once the function
+// has returned.
+//
+// The onLine function is called with the stream on which the line was
+// captured and the line itself (without any terminating newline).  If
+// the output of a stream does not end with a newline, onLine is called
+// for the final, incomplete line when the supplied function returns.
+//
+// Calls to onLine are serialized; onLine need not be safe for
+// concurrent use, but should return quickly since the captured
+// function may block writing output while onLine is running.
+//
+// The returned error is the error returned by the supplied function
+// joined with any ErrStdoutCapture and/or ErrStderrCapture errors.
+//
+// Of the available options, only WithBlockWarning, WithCopyFunc and
+// WithDrainTimeout apply to OutputStream; any other options are
+// ignored.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		err := capture.OutputStream(
+//		   func(s capture.Stream, line string) {
+//		      fmt.Printf("%s: %s\n", s, line)
+//		   },
+//		   func () error {
+//		      return doSomething()
+//		   })
+//
+//		fmt.Printf("error: %v", err)
+//	  }
+func OutputStream(onLine func(stream Stream, line string), fn func() error, opts ...Option) error {
+	cfg := newConfig(opts)
+
+	wmu := &sync.Mutex{}
+	writer := func(s Stream) *lineWriter {
+		return &lineWriter{fn: func(line string) {
+			wmu.Lock()
+			defer wmu.Unlock()
+			onLine(s, line)
+		}}
+	}
+	stdout := writer(StdoutStream)
+	stderr := writer(StderrStream)
+
+	o := redirect(cfg.watch(StdoutStream, stdout), cfg.watch(StderrStream, stderr), cfg.copier(), cfg.drain, fn)
+
+	stdout.flush()
+	stderr.flush()
+
+	return o.join()
+}
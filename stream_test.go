@@ -0,0 +1,80 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	testcases := []struct {
+		stream Stream
+		result string
+	}{
+		{stream: StdoutStream, result: "stdout"},
+		{stream: StderrStream, result: "stderr"},
+		{stream: Stream(-1), result: "unknown"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.result, func(t *testing.T) {
+			wanted := tc.result
+			got := tc.stream.String()
+			if wanted != got {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	}
+}
+
+func TestOutputStream(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	stdout := []string{}
+	stderr := []string{}
+
+	// ACT
+	err := OutputStream(
+		func(s Stream, line string) {
+			switch s {
+			case StdoutStream:
+				stdout = append(stdout, line)
+			case StderrStream:
+				stderr = append(stderr, line)
+			}
+		},
+		func() error {
+			fmt.Println("to stdout (1)")
+			fmt.Print("to std")
+			fmt.Println("out (2)")
+			os.Stderr.WriteString("to stderr (1)\r\n\n")
+			os.Stderr.WriteString("to stderr (2)")
+			return fnerr
+		})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout lines", func(t *testing.T) {
+		wanted := []string{"to stdout (1)", "to stdout (2)"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("stderr lines", func(t *testing.T) {
+		wanted := []string{"to stderr (1)", "", "to stderr (2)"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
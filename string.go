@@ -0,0 +1,31 @@
+package capture
+
+// OutputString captures the stdout and stderr output produced during
+// execution of a supplied function, returning the captured output of
+// each stream as a single string.
+//
+// Unlike Output, the captured output is not split into lines; newlines
+// (including any trailing newline) are preserved.  If no output was
+// written to a stream, the corresponding result is "".
+//
+// Error handling is identical to Output; if ErrStdoutCapture or
+// ErrStderrCapture is returned, the corresponding captured output is
+// discarded.
+//
+// Options are applied as for Output.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, stderr, err := capture.OutputString(func () error {
+//		   return doSomething()
+//		})
+//
+//		if !strings.Contains(stdout, "success") {
+//		   fmt.Println("something went wrong")
+//		}
+//	  }
+func OutputString(fn func() error, opts ...Option) (string, string, error) {
+	stdout, stderr, err := output(fn, opts...)
+	return string(stdout), string(stderr), err
+}
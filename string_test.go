@@ -0,0 +1,77 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestOutputString(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	stdout, stderr, err := OutputString(func() error {
+		fmt.Println("to stdout (1)")
+		fmt.Println("to stdout (2)")
+		os.Stderr.WriteString("to stderr\n\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout captured", func(t *testing.T) {
+		wanted := "to stdout (1)\nto stdout (2)\n"
+		got := stdout
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("stderr captured", func(t *testing.T) {
+		wanted := "to stderr\n\n"
+		got := stderr
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when error copying captured buffers", func(t *testing.T) {
+		// ARRANGE
+		cpyerr := fmt.Errorf("copy error")
+		cp := func(dst io.Writer, src io.Reader) (int64, error) { _, _ = io.Copy(dst, src); return 0, cpyerr }
+
+		// ACT
+		stdout, stderr, err := OutputString(func() error { fmt.Println("some output"); return nil }, WithCopyFunc(cp))
+
+		// ASSERT
+		t.Run("errors", func(t *testing.T) {
+			got := err
+
+			wanted := ErrStdoutCapture
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+
+			wanted = ErrStderrCapture
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("output discarded", func(t *testing.T) {
+			if stdout != "" || stderr != "" {
+				t.Errorf("\nwanted: \"\", \"\"\ngot   : %q, %q", stdout, stderr)
+			}
+		})
+	})
+}
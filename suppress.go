@@ -0,0 +1,25 @@
+package capture
+
+import "io"
+
+// Suppress silences any stdout and stderr output produced during
+// execution of a supplied function, returning only the error returned
+// by the function.
+//
+// Output is read from the redirected streams and discarded as it is
+// written; no output is retained, so there is no memory cost however
+// much output the function produces.  os.Stdout and os.Stderr are
+// restored when the function returns, or panics.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		err := capture.Suppress(func () error {
+//		   return doSomethingNoisy()
+//		})
+//
+//		fmt.Printf("error: %v", err)
+//	  }
+func Suppress(fn func() error) error {
+	return redirect(io.Discard, io.Discard, io.Copy, 0, fn).err
+}
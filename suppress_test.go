@@ -0,0 +1,55 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestSuppress(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	ogout, ogerr := os.Stdout, os.Stderr
+
+	// ACT
+	var err error
+	stdout, stderr, _ := Output(func() error {
+		err = Suppress(func() error {
+			fmt.Println("to stdout")
+			os.Stderr.WriteString("to stderr")
+			return fnerr
+		})
+		return nil
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("output suppressed", func(t *testing.T) {
+		if stdout != nil || stderr != nil {
+			t.Errorf("\nwanted: no output\ngot   : %v %v", stdout, stderr)
+		}
+	})
+
+	t.Run("when function panics", func(t *testing.T) {
+		// ARRANGE
+		defer func() {
+			_ = recover()
+
+			// ASSERT
+			if os.Stdout != ogout || os.Stderr != ogerr {
+				t.Errorf("os.Stdout and/or os.Stderr not restored")
+			}
+		}()
+
+		// ACT
+		_ = Suppress(func() error { panic("panic") })
+	})
+}
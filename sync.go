@@ -0,0 +1,106 @@
+package capture
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// OutputSync captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, without starting any
+// goroutines.
+//
+// Rather than a pipe (which must be read concurrently to avoid the
+// function blocking once the pipe buffer is full) os.Stdout and
+// os.Stderr are redirected to temporary files, which are read once the
+// function has returned.  Output is therefore not available until the
+// function returns and cannot be streamed or teed, but the capture is
+// entirely synchronous, which can make tests (and any -race reports)
+// simpler to reason about.
+//
+// The temporary files are removed when the capture is complete.  If a
+// temporary file cannot be created, the function is not called and
+// ErrStdoutCapture or ErrStderrCapture is returned, wrapping the
+// error.  Otherwise, error handling is identical to Output.
+//
+// Example:
+//
+//	  func TestDoSomething(t *testing.T) {
+//		stdout, stderr, err := capture.OutputSync(func () error {
+//		   return doSomething()
+//		})
+//		...
+//	  }
+func OutputSync(fn func() error) ([]string, []string, error) {
+	mu.lock()
+	defer mu.unlock()
+
+	h := startHook()
+
+	stopout, err := captureSync(&os.Stdout)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrStdoutCapture, err)
+		h.stop(err)
+		return nil, nil, err
+	}
+
+	stoperr, err := captureSync(&os.Stderr)
+	if err != nil {
+		_, _ = stopout()
+		err = fmt.Errorf("%w: %w", ErrStderrCapture, err)
+		h.stop(err)
+		return nil, nil, err
+	}
+
+	var (
+		o       outcome
+		stdout  []byte
+		stderr  []byte
+		stopped bool
+	)
+	stop := func() {
+		if stopped {
+			return
+		}
+		stopped = true
+
+		if stderr, err = stoperr(); err != nil {
+			stderr, o.stderr = nil, fmt.Errorf("%w: %w", ErrStderrCapture, err)
+		}
+		if stdout, err = stopout(); err != nil {
+			stdout, o.stdout = nil, fmt.Errorf("%w: %w", ErrStdoutCapture, err)
+		}
+	}
+	defer stop() // ensures the files are restored if the function panics
+
+	o.err = fn()
+	stop()
+
+	h.record(StdoutStream, stdout)
+	h.record(StderrStream, stderr)
+	h.stop(o.join())
+
+	return lines(string(stdout)), lines(string(stderr)), o.join()
+}
+
+// captureSync redirects a file to a temporary file, returning a
+// function that restores the file and returns the content written to
+// the temporary file, which is then removed.
+func captureSync(t **os.File) (func() ([]byte, error), error) {
+	f, err := os.CreateTemp("", "capture-*")
+	if err != nil {
+		return nil, err
+	}
+	rd := install(t, f)
+
+	return func() ([]byte, error) {
+		rd.restore()
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return io.ReadAll(f)
+	}, nil
+}
@@ -0,0 +1,105 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestOutputSync(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	og := os.Stdout
+	before := runtime.NumGoroutine()
+	var during int
+
+	// ACT
+	stdout, stderr, err := OutputSync(func() error {
+		during = runtime.NumGoroutine()
+		fmt.Println("to stdout")
+		os.Stderr.WriteString("to stderr\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		wanted := []string{"to stdout"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		wanted := []string{"to stderr"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("no goroutines started", func(t *testing.T) {
+		wanted := before
+		got := during
+		if wanted != got {
+			t.Errorf("\nwanted: %d\ngot   : %d", wanted, got)
+		}
+	})
+
+	t.Run("stdout restored", func(t *testing.T) {
+		if os.Stdout != og {
+			t.Error("os.Stdout was not restored")
+		}
+	})
+
+	t.Run("when output exceeds a pipe buffer", func(t *testing.T) {
+		// ARRANGE
+		line := strings.Repeat("x", 1023)
+
+		// ACT
+		stdout, _, err := OutputSync(func() error {
+			for i := 0; i < 1024; i++ {
+				fmt.Println(line)
+			}
+			return nil
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", err)
+		}
+		wanted := 1024
+		got := len(stdout)
+		if wanted != got {
+			t.Errorf("\nwanted: %d lines\ngot   : %d lines", wanted, got)
+		}
+	})
+
+	t.Run("when the function panics", func(t *testing.T) {
+		// ARRANGE
+		defer func() {
+			// ASSERT
+			if r := recover(); r == nil {
+				t.Error("expected a panic")
+			}
+			if os.Stdout != og {
+				t.Error("os.Stdout was not restored")
+			}
+		}()
+
+		// ACT
+		_, _, _ = OutputSync(func() error { panic("boom") })
+	})
+}
@@ -0,0 +1,45 @@
+package capture
+
+// Line is a line of captured output, identifying the stream on which
+// it was captured.
+type Line struct {
+	Stream Stream
+	Text   string
+}
+
+// OutputTagged captures the stdout and stderr output produced during
+// execution of a supplied function, returning the captured lines of
+// both streams in a single slice, in the order in which they were
+// captured, each identifying the stream on which it was written.
+//
+// Each stream is captured separately, so the stream of every line is
+// always correctly identified.  However, the relative ordering of lines
+// on different streams reflects the order in which lines were read from
+// the two streams and is therefore best-effort: lines written to
+// different streams in very quick succession may be captured out of
+// order.  The order of lines within each stream is always preserved.
+//
+// If there is no output, the returned slice is nil.  The returned
+// error is the error returned by the function joined with any
+// ErrStdoutCapture and/or ErrStderrCapture errors.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		lines, err := capture.OutputTagged(func () error {
+//		   fmt.Println("to stdout")
+//		   fmt.Fprintln(os.Stderr, "to stderr")
+//		   return nil
+//		})
+//
+//		for _, l := range lines {
+//		   fmt.Printf("%s: %s\n", l.Stream, l.Text)
+//		}
+//	  }
+func OutputTagged(fn func() error) ([]Line, error) {
+	var result []Line
+	err := OutputStream(func(s Stream, line string) {
+		result = append(result, Line{Stream: s, Text: line})
+	}, fn)
+	return result, err
+}
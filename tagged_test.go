@@ -0,0 +1,62 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputTagged(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	result, err := OutputTagged(func() error {
+		fmt.Println("to stdout (1)")
+		fmt.Println("to stdout (2)")
+		os.Stderr.WriteString("to stderr (1)\n")
+		os.Stderr.WriteString("to stderr (2)")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("lines tagged", func(t *testing.T) {
+		stdout := []string{}
+		stderr := []string{}
+		for _, l := range result {
+			switch l.Stream {
+			case StdoutStream:
+				stdout = append(stdout, l.Text)
+			case StderrStream:
+				stderr = append(stderr, l.Text)
+			}
+		}
+
+		wanted := [][]string{{"to stdout (1)", "to stdout (2)"}, {"to stderr (1)", "to stderr (2)"}}
+		got := [][]string{stdout, stderr}
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("when no output is produced", func(t *testing.T) {
+		// ACT
+		result, _ := OutputTagged(func() error { return nil })
+
+		// ASSERT
+		got := result
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package capture
+
+// ring is a fixed-size buffer retaining the most recent n lines added
+// to it.
+type ring struct {
+	lines []string
+	next  int
+	full  bool
+}
+
+// newRing returns a ring retaining at most n lines.  If n is zero (or
+// less) the ring retains no lines.
+func newRing(n int) *ring {
+	return &ring{lines: make([]string, max(n, 0))}
+}
+
+// add adds a line to the ring, replacing the oldest line if the ring
+// is full.
+func (r *ring) add(s string) {
+	if len(r.lines) == 0 {
+		return
+	}
+	r.lines[r.next] = s
+	r.next = (r.next + 1) % len(r.lines)
+	r.full = r.full || r.next == 0
+}
+
+// result returns the lines in the ring, oldest first.  If the ring is
+// empty, nil is returned.
+func (r *ring) result() []string {
+	if !r.full {
+		if r.next == 0 {
+			return nil
+		}
+		return append([]string(nil), r.lines[:r.next]...)
+	}
+	return append(append([]string(nil), r.lines[r.next:]...), r.lines[:r.next]...)
+}
+
+// OutputTail captures the stdout and stderr output produced during
+// execution of a supplied function, retaining only the final n lines of
+// each stream.
+//
+// Output is processed line by line as it is captured, with only the
+// most recent n lines of each stream retained, so memory use is bounded
+// regardless of the volume of output.  The returned lines are in the
+// order in which they were captured (oldest retained line first).  If
+// n is zero (or less), no lines are retained and nil is returned for
+// both streams.
+//
+// The returned error is the error returned by the function joined with
+// any ErrStdoutCapture and/or ErrStderrCapture errors.
+//
+// Example:
+//
+//	  func TestSomething(t *testing.T) {
+//		_, stderr, err := capture.OutputTail(10, func () error {
+//		   return doSomethingVerbose()
+//		})
+//		if err != nil {
+//		   t.Errorf("error: %v\nlast 10 lines of log:\n%s", err, strings.Join(stderr, "\n"))
+//		}
+//	  }
+func OutputTail(n int, fn func() error) ([]string, []string, error) {
+	stdout := newRing(n)
+	stderr := newRing(n)
+
+	err := OutputStream(func(s Stream, line string) {
+		switch s {
+		case StdoutStream:
+			stdout.add(line)
+		case StderrStream:
+			stderr.add(line)
+		}
+	}, fn)
+
+	return stdout.result(), stderr.result(), err
+}
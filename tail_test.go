@@ -0,0 +1,94 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestRing(t *testing.T) {
+	testcases := []struct {
+		name   string
+		size   int
+		lines  []string
+		result []string
+	}{
+		{name: "empty", size: 3, result: nil},
+		{name: "partially filled", size: 3, lines: []string{"a", "b"}, result: []string{"a", "b"}},
+		{name: "exactly filled", size: 3, lines: []string{"a", "b", "c"}, result: []string{"a", "b", "c"}},
+		{name: "wrapped", size: 3, lines: []string{"a", "b", "c", "d", "e"}, result: []string{"c", "d", "e"}},
+		{name: "zero size", size: 0, lines: []string{"a"}, result: nil},
+		{name: "negative size", size: -1, lines: []string{"a"}, result: nil},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// ARRANGE
+			r := newRing(tc.size)
+
+			// ACT
+			for _, s := range tc.lines {
+				r.add(s)
+			}
+
+			// ASSERT
+			wanted := tc.result
+			got := r.result()
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+	}
+}
+
+func TestOutputTail(t *testing.T) {
+	// ACT
+	stdout, stderr, err := OutputTail(2, func() error {
+		for i := 1; i <= 100; i++ {
+			fmt.Printf("stdout %d\n", i)
+		}
+		os.Stderr.WriteString("stderr 1\n")
+		return nil
+	})
+
+	// ASSERT
+	t.Run("no error", func(t *testing.T) {
+		got := err
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+
+	t.Run("stdout tail", func(t *testing.T) {
+		wanted := []string{"stdout 99", "stdout 100"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stderr tail", func(t *testing.T) {
+		wanted := []string{"stderr 1"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+}
+
+func TestOutputTailNegative(t *testing.T) {
+	// ACT
+	stdout, stderr, err := OutputTail(-1, func() error {
+		fmt.Println("stdout")
+		os.Stderr.WriteString("stderr\n")
+		return nil
+	})
+
+	// ASSERT
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if stdout != nil || stderr != nil {
+		t.Errorf("\nwanted: nil, nil\ngot   : %q, %q", stdout, stderr)
+	}
+}
@@ -0,0 +1,32 @@
+package capture
+
+import "io"
+
+// OutputTee captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, additionally
+// writing the captured output to the supplied writers as it is
+// captured.
+//
+// If a writer is nil, the corresponding output is captured but is
+// not written to any other writer.
+//
+// Passing the original os.Stdout and os.Stderr (obtained before
+// calling OutputTee) provides a passthrough, with output appearing
+// on the terminal as it is produced as well as being captured.
+//
+// Error handling is identical to Output.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		stdout, stderr, err := capture.OutputTee(os.Stdout, os.Stderr, func () error {
+//		   return doSomething()
+//		})
+//
+//		fmt.Printf("stdout: %v", stdout)
+//		fmt.Printf("stderr: %v", stderr)
+//		fmt.Printf("error: %v", err)
+//	  }
+func OutputTee(stdoutW, stderrW io.Writer, fn func() error) ([]string, []string, error) {
+	return Output(fn, withTees(stdoutW, stderrW))
+}
@@ -0,0 +1,81 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputTee(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	teeout := &bytes.Buffer{}
+	teeerr := &bytes.Buffer{}
+
+	// ACT
+	stdout, stderr, err := OutputTee(teeout, teeerr, func() error {
+		fmt.Println("to stdout")
+		os.Stderr.WriteString("to stderr\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout captured", func(t *testing.T) {
+		wanted := []string{"to stdout"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stderr captured", func(t *testing.T) {
+		wanted := []string{"to stderr"}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stdout tee'd", func(t *testing.T) {
+		wanted := "to stdout\n"
+		got := teeout.String()
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("stderr tee'd", func(t *testing.T) {
+		wanted := "to stderr\n"
+		got := teeerr.String()
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when writers are nil", func(t *testing.T) {
+		// ACT
+		stdout, _, err := OutputTee(nil, nil, func() error { fmt.Println("some output"); return nil })
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", err)
+		}
+
+		wanted := []string{"some output"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package capture
+
+import "io"
+
+// OutputTeeMap captures the stdout and stderr output produced during
+// execution of a supplied function, as for OutputMap, applying a
+// supplied transformation to each captured line, while writing the
+// original, untransformed output of both stdout and stderr to a
+// supplied writer as it is captured (as for WithTee).
+//
+// This supports showing the raw output live while asserting on
+// normalised lines.  OutputTeeMapped provides the reverse, with the
+// transformed output written to the writer and the original lines
+// returned.
+//
+// Error handling is identical to Output.
+//
+// Example:
+//
+//	  func TestDoSomething(t *testing.T) {
+//		stdout, _, _ := capture.OutputTeeMap(os.Stdout,
+//		   func(s string) string { return timestamp.ReplaceAllString(s, "<time>") },
+//		   func () error {
+//		      return doSomething()
+//		   })
+//		...
+//	  }
+func OutputTeeMap(w io.Writer, transform func(string) string, fn func() error) ([]string, []string, error) {
+	stdout, stderr, err := Output(fn, WithTee(w))
+	return mapLines(stdout, transform), mapLines(stderr, transform), err
+}
+
+// OutputTeeMapped captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, while writing the
+// output of both stdout and stderr to a supplied writer as it is
+// captured, applying a supplied transformation to each line written to
+// the writer.  The returned lines are the original, untransformed
+// lines.
+//
+// As for OutputMap, a line for which the transformation returns "" is
+// not written to the writer.  Each transformed line is written with a
+// terminating newline, except for any final line of a stream that did
+// not end with a newline; that line is written, without a newline,
+// once the function has returned.
+//
+// Error handling is identical to Output.
+//
+// Example:
+//
+//	  func TestDoSomething(t *testing.T) {
+//		stdout, _, _ := capture.OutputTeeMapped(os.Stdout,
+//		   func(s string) string { return "  | " + s },
+//		   func () error {
+//		      return doSomething()
+//		   })
+//		...
+//	  }
+func OutputTeeMapped(w io.Writer, transform func(string) string, fn func() error) ([]string, []string, error) {
+	sw := &syncWriter{w: w}
+	tee := func(nl string) func(string) {
+		return func(s string) {
+			if s = transform(s); s != "" {
+				_, _ = io.WriteString(sw, s+nl)
+			}
+		}
+	}
+	outw := &lineWriter{fn: tee("\n")}
+	errw := &lineWriter{fn: tee("\n")}
+
+	stdout, stderr, err := Output(fn, withTees(outw, errw))
+
+	// any incomplete final lines are written without a newline
+	outw.fn, errw.fn = tee(""), tee("")
+	outw.flush()
+	errw.flush()
+
+	return stdout, stderr, err
+}
@@ -0,0 +1,127 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOutputTeeMap(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	tee := &bytes.Buffer{}
+	transform := func(s string) string {
+		if s == "drop" {
+			return ""
+		}
+		return strings.ToUpper(s)
+	}
+
+	// ACT
+	stdout, stderr, err := OutputTeeMap(tee, transform, func() error {
+		fmt.Println("to stdout")
+		fmt.Println("drop")
+		os.Stderr.WriteString("to stderr\n")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("returns transformed lines", func(t *testing.T) {
+		wanted := [][]string{{"TO STDOUT"}, {"TO STDERR"}}
+		got := [][]string{stdout, stderr}
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("tee'd original output", func(t *testing.T) {
+		content := tee.String()
+		for _, s := range []string{"to stdout\ndrop\n", "to stderr\n"} {
+			if !strings.Contains(content, s) {
+				t.Errorf("\nwanted: tee containing %q\ngot   : %q", s, content)
+			}
+		}
+		if wanted, got := len("to stdout\ndrop\nto stderr\n"), len(content); wanted != got {
+			t.Errorf("\nwanted: %d bytes\ngot   : %d", wanted, got)
+		}
+	})
+}
+
+func TestOutputTeeMapped(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	tee := &bytes.Buffer{}
+	transform := func(s string) string {
+		if s == "drop" {
+			return ""
+		}
+		return strings.ToUpper(s)
+	}
+
+	// ACT
+	stdout, stderr, err := OutputTeeMapped(tee, transform, func() error {
+		fmt.Println("to stdout")
+		fmt.Println("drop")
+		fmt.Print("partial")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("returns original lines", func(t *testing.T) {
+		wanted := [][]string{{"to stdout", "drop", "partial"}, nil}
+		got := [][]string{stdout, stderr}
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("tee'd transformed output", func(t *testing.T) {
+		wanted := "TO STDOUT\nPARTIAL"
+		got := tee.String()
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		// ARRANGE
+		tee := &bytes.Buffer{}
+
+		// ACT
+		_, stderr, err := OutputTeeMapped(tee, strings.ToUpper, func() error {
+			os.Stderr.WriteString("to stderr\n")
+			return nil
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if wanted, got := []string{"to stderr"}, stderr; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		if wanted, got := "TO STDERR\n", tee.String(); wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package capture
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// TimedLine is a line of captured output, recording when it was
+// captured.
+type TimedLine struct {
+	At   time.Duration // the time from the start of the capture (when the function is called)
+	Text string
+}
+
+// OutputTimed captures the stdout and stderr output produced during
+// execution of a supplied function, recording the time at which each
+// line was captured as an offset from the start of the capture.
+//
+// The start of the capture is the time at which the function is
+// called, once os.Stdout and os.Stderr have been redirected; the time
+// taken to establish the capture is not included in the offsets.  A
+// line captured before the function is called (e.g. written by another
+// goroutine) is timed from when OutputTimed was called.
+//
+// A line is timed when it is completed, i.e. when the newline ending
+// the line is read from the pipe; the resolution is therefore that of
+// the reads from the pipe, not of the writes made by the function.  A
+// final line without a newline is timed when the function returns.
+//
+// This allows assertions on timing-sensitive output, e.g. that a
+// progress line appears before a result, or that throttled events are
+// appropriately spaced.  If there is no output for a stream, the result
+// for that stream is nil.
+//
+// Error handling is identical to OutputStream.
+//
+// Example:
+//
+//	  func TestThrottle(t *testing.T) {
+//		stdout, _, _ := capture.OutputTimed(func () error {
+//		   return emitThrottled(2, 100 * time.Millisecond)
+//		})
+//		if gap := stdout[1].At - stdout[0].At; gap < 100 * time.Millisecond {
+//		   t.Errorf("events not throttled: %v apart", gap)
+//		}
+//	  }
+func OutputTimed(fn func() error) ([]TimedLine, []TimedLine, error) {
+	var stdout, stderr []TimedLine
+
+	// a line may be captured before the function is called (e.g. written
+	// by a background goroutine), so the start is initialised before the
+	// capture and reset when the function is called
+	var start atomic.Pointer[time.Time]
+	now := time.Now()
+	start.Store(&now)
+
+	err := OutputStream(func(s Stream, line string) {
+		l := TimedLine{At: time.Since(*start.Load()), Text: line}
+		switch s {
+		case StdoutStream:
+			stdout = append(stdout, l)
+		case StderrStream:
+			stderr = append(stderr, l)
+		}
+	}, func() error {
+		now := time.Now()
+		start.Store(&now)
+		return fn()
+	})
+
+	return stdout, stderr, err
+}
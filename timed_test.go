@@ -0,0 +1,81 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestOutputTimed(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	const delay = 50 * time.Millisecond
+
+	// ACT
+	stdout, stderr, err := OutputTimed(func() error {
+		fmt.Println("first")
+		time.Sleep(delay)
+		fmt.Println("second")
+		os.Stderr.WriteString("unterminated")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		if len(stdout) != 2 || stdout[0].Text != "first" || stdout[1].Text != "second" {
+			t.Fatalf("\nwanted: [first second]\ngot   : %v", stdout)
+		}
+
+		wanted := delay
+		got := stdout[1].At - stdout[0].At
+		if got < wanted {
+			t.Errorf("\nwanted: >= %v apart\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		if len(stderr) != 1 || stderr[0].Text != "unterminated" {
+			t.Fatalf("\nwanted: [unterminated]\ngot   : %v", stderr)
+		}
+
+		wanted := stdout[1].At
+		got := stderr[0].At
+		if got < wanted {
+			t.Errorf("\nwanted: >= %v\ngot   : %v", wanted, got)
+		}
+	})
+}
+
+func TestOutputTimedStart(t *testing.T) {
+	// ARRANGE
+	const delay = 50 * time.Millisecond
+	OnStart = func() { time.Sleep(delay) }
+	defer func() { OnStart = nil }()
+
+	// ACT
+	stdout, _, _ := OutputTimed(func() error {
+		fmt.Println("immediate")
+		return nil
+	})
+
+	// ASSERT
+	if len(stdout) != 1 {
+		t.Fatalf("\nwanted: [immediate]\ngot   : %v", stdout)
+	}
+
+	wanted := delay
+	got := stdout[0].At
+	if got >= wanted {
+		t.Errorf("\nwanted: < %v\ngot   : %v", wanted, got)
+	}
+}
@@ -0,0 +1,35 @@
+package capture
+
+import "time"
+
+// OutputTiming captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, also returning the
+// wall-clock time taken by the function.
+//
+// Only the call of the function is timed; the time taken to establish
+// the capture and to complete it (copying any remaining output from the
+// pipes) is not included.  Timings are subject to the usual vagaries of
+// scheduling and are best used for coarse assertions only.
+//
+// Example:
+//
+//	  func TestCache(t *testing.T) {
+//		stdout, _, elapsed, err := capture.OutputTiming(func () error {
+//		   return lookup("key")
+//		})
+//		if elapsed > 10 * time.Millisecond {
+//		   t.Errorf("lookup not cached: took %v", elapsed)
+//		}
+//		...
+//	  }
+func OutputTiming(fn func() error) ([]string, []string, time.Duration, error) {
+	var elapsed time.Duration
+
+	stdout, stderr, err := Output(func() error {
+		start := time.Now()
+		defer func() { elapsed = time.Since(start) }()
+		return fn()
+	})
+
+	return stdout, stderr, elapsed, err
+}
@@ -0,0 +1,49 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOutputTiming(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	delay := 20 * time.Millisecond
+
+	// ACT
+	start := time.Now()
+	stdout, stderr, elapsed, err := OutputTiming(func() error {
+		fmt.Println("to stdout")
+		time.Sleep(delay)
+		os.Stderr.WriteString("to stderr\n")
+		return fnerr
+	})
+	total := time.Since(start)
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("output", func(t *testing.T) {
+		wanted := [][]string{{"to stdout"}, {"to stderr"}}
+		got := [][]string{stdout, stderr}
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("elapsed", func(t *testing.T) {
+		if elapsed < delay || elapsed > total {
+			t.Errorf("\nwanted: %v <= elapsed <= %v\ngot   : %v", delay, total, elapsed)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+)
+
+// OutputToFile captures the stdout and stderr output produced during
+// execution of a supplied function, streaming the captured output to
+// temporary files rather than holding it in memory.  The paths of the
+// files holding the stdout and stderr output are returned.
+//
+// This allows the capture of volumes of output that cannot be held in
+// memory.  The files are closed (and the captured output fully copied
+// to them) before OutputToFile returns.  The caller is responsible for
+// removing the files.
+//
+// If a temporary file cannot be created, the function is not called and
+// ErrStdoutCapture or ErrStderrCapture is returned, wrapping the error.
+// Otherwise error handling is as for Output: if ErrStdoutCapture or
+// ErrStderrCapture is returned, the file for the stream is removed and
+// the corresponding path is "".  If the function panics, both files
+// are removed.
+//
+// Of the available options, only WithCopyFunc and WithReadChunkSize
+// apply to OutputToFile; any other options are ignored.
+//
+// Example:
+//
+//	  func TestLargeOutput(t *testing.T) {
+//		stdout, stderr, err := capture.OutputToFile(func () error {
+//		   return generateReport()
+//		})
+//		defer os.Remove(stdout)
+//		defer os.Remove(stderr)
+//		...
+//	  }
+func OutputToFile(fn func() error, opts ...Option) (string, string, error) {
+	outf, err := os.CreateTemp("", "capture-stdout-*")
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %w", ErrStdoutCapture, err)
+	}
+
+	errf, err := os.CreateTemp("", "capture-stderr-*")
+	if err != nil {
+		_ = outf.Close()
+		_ = os.Remove(outf.Name())
+		return "", "", fmt.Errorf("%w: %w", ErrStderrCapture, err)
+	}
+
+	// the files are closed and removed if the function panics
+	panicked := true
+	defer func() {
+		if panicked {
+			for _, f := range []*os.File{outf, errf} {
+				_ = f.Close()
+				_ = os.Remove(f.Name())
+			}
+		}
+	}()
+
+	o := redirect(outf, errf, newConfig(opts).copier(), 0, fn)
+	panicked = false
+
+	if err := outf.Close(); err != nil && o.stdout == nil {
+		o.stdout = fmt.Errorf("%w: %w", ErrStdoutCapture, err)
+	}
+	if err := errf.Close(); err != nil && o.stderr == nil {
+		o.stderr = fmt.Errorf("%w: %w", ErrStderrCapture, err)
+	}
+
+	return keepFile(outf, o.stdout), keepFile(errf, o.stderr), o.join()
+}
+
+// keepFile returns the name of a file holding captured output.  If the
+// capture failed (err is not nil) the file is removed and "" is
+// returned.
+func keepFile(f *os.File, err error) string {
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return ""
+	}
+	return f.Name()
+}
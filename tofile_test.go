@@ -0,0 +1,99 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestOutputToFile(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	line := strings.Repeat("x", 1023)
+
+	// ACT
+	stdout, stderr, err := OutputToFile(func() error {
+		for i := 0; i < 1024; i++ {
+			fmt.Println(line)
+		}
+		os.Stderr.WriteString("to stderr\n")
+		return fnerr
+	})
+	defer os.Remove(stdout)
+	defer os.Remove(stderr)
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		b, err := os.ReadFile(stdout)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		wanted := 1024 * 1024
+		got := len(b)
+		if wanted != got {
+			t.Errorf("\nwanted: %d bytes\ngot   : %d bytes", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		b, err := os.ReadFile(stderr)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		wanted := "to stderr\n"
+		got := string(b)
+		if wanted != got {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when copying fails", func(t *testing.T) {
+		// ARRANGE
+		cp := func(dst io.Writer, src io.Reader) (int64, error) {
+			_, _ = io.Copy(dst, src)
+			return 0, errors.New("copy error")
+		}
+
+		// ACT
+		stdout, stderr, err := OutputToFile(func() error { return nil }, WithCopyFunc(cp))
+
+		// ASSERT
+		if !errors.Is(err, ErrStdoutCapture) || !errors.Is(err, ErrStderrCapture) {
+			t.Errorf("\nwanted: %v and %v\ngot   : %v", ErrStdoutCapture, ErrStderrCapture, err)
+		}
+		if stdout != "" || stderr != "" {
+			t.Errorf("\nwanted: \"\", \"\"\ngot   : %q, %q", stdout, stderr)
+		}
+	})
+
+	t.Run("when function panics", func(t *testing.T) {
+		// ARRANGE
+		tmp := t.TempDir()
+		setTempDir(t, tmp)
+
+		// ACT
+		func() {
+			defer func() { _ = recover() }()
+			_, _, _ = OutputToFile(func() error {
+				os.Stdout.WriteString("to stdout\n")
+				panic("function panicked")
+			})
+		}()
+
+		// ASSERT
+		if files, _ := os.ReadDir(tmp); len(files) > 0 {
+			t.Errorf("temporary files not removed: %v", files)
+		}
+	})
+}
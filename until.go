@@ -0,0 +1,77 @@
+package capture
+
+import "strings"
+
+// OutputUntil captures the stdout and stderr output produced during
+// execution of a supplied function up to and including the first line
+// (of either stream) containing a marker, signalling the function once
+// the marker has been captured.
+//
+// The function is passed a ready channel, which is closed once a line
+// containing the marker has been captured.  The function may then
+// proceed, e.g. to exercise a server that has reported that it is ready,
+// returning once it has done so.  As with OutputFirst, this requires
+// cooperation from the function.
+//
+// Any output after the marker line (i.e. produced before the function
+// returns) is discarded.  The streams are captured independently, so
+// output written to the other stream before the marker line was
+// written is discarded if it is captured after the marker line.  If no
+// line contains the marker, the channel is not closed and all output is
+// returned.  An empty marker is contained in (and so is matched by) the
+// first line.
+//
+// Error handling is identical to OutputStream.
+//
+// Example:
+//
+//	  func TestServer(t *testing.T) {
+//		stdout, _, err := capture.OutputUntil("listening", func (ready <-chan struct{}) error {
+//		   srv := startServer() // prints "listening on :8080" once ready
+//		   defer srv.Close()
+//
+//		   select {
+//		   case <-ready:
+//		      return exerciseServer()
+//		   case <-time.After(5 * time.Second):
+//		      return errors.New("server not ready")
+//		   }
+//		})
+//		...
+//	  }
+func OutputUntil(marker string, fn func(ready <-chan struct{}) error) ([]string, []string, error) {
+	return outputUntil(func(_ Stream, line string) bool {
+		return strings.Contains(line, marker)
+	}, fn)
+}
+
+// outputUntil captures output as for OutputUntil, up to and including
+// the first line for which a match function returns true.
+func outputUntil(match func(Stream, string) bool, fn func(<-chan struct{}) error) ([]string, []string, error) {
+	ready := make(chan struct{})
+
+	var (
+		stdout []string
+		stderr []string
+		seen   bool
+	)
+	err := OutputStream(func(st Stream, line string) {
+		if seen {
+			return
+		}
+
+		switch st {
+		case StdoutStream:
+			stdout = append(stdout, line)
+		case StderrStream:
+			stderr = append(stderr, line)
+		}
+
+		if match(st, line) {
+			seen = true
+			close(ready)
+		}
+	}, func() error { return fn(ready) })
+
+	return stdout, stderr, err
+}
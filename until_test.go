@@ -0,0 +1,101 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOutputUntil(t *testing.T) {
+	t.Run("when marker is captured", func(t *testing.T) {
+		// ARRANGE
+		fnerr := errors.New("function error")
+
+		// ACT
+		stdout, stderr, err := OutputUntil("ready", func(ready <-chan struct{}) error {
+			fmt.Println("starting")
+			fmt.Println("server ready on :8080")
+			select {
+			case <-ready:
+			case <-time.After(time.Second):
+				return errors.New("ready not signalled")
+			}
+			fmt.Println("after ready")
+			return fnerr
+		})
+
+		// ASSERT
+		t.Run("returns error", func(t *testing.T) {
+			wanted := fnerr
+			got := err
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+
+		t.Run("stdout", func(t *testing.T) {
+			wanted := []string{"starting", "server ready on :8080"}
+			got := stdout
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+
+		t.Run("stderr", func(t *testing.T) {
+			var wanted []string
+			got := stderr
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+			}
+		})
+	})
+
+	t.Run("when marker is captured on stderr", func(t *testing.T) {
+		// ACT
+		stdout, stderr, err := OutputUntil("ready", func(ready <-chan struct{}) error {
+			os.Stderr.WriteString("ready\n")
+			<-ready
+			os.Stderr.WriteString("after ready\n")
+			return nil
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if wanted, got := [][]string{nil, {"ready"}}, [][]string{stdout, stderr}; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when marker is not captured", func(t *testing.T) {
+		// ARRANGE
+		var signalled bool
+
+		// ACT
+		stdout, _, err := OutputUntil("ready", func(ready <-chan struct{}) error {
+			fmt.Println("starting")
+			fmt.Println("failed")
+			select {
+			case <-ready:
+				signalled = true
+			case <-time.After(10 * time.Millisecond):
+			}
+			return nil
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if signalled {
+			t.Error("ready signalled")
+		}
+		if wanted, got := []string{"starting", "failed"}, stdout; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
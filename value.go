@@ -0,0 +1,28 @@
+package capture
+
+// OutputValue captures the stdout and stderr output produced during
+// execution of a supplied function that returns a value and an error,
+// as for Output, returning the value returned by the function together
+// with the captured output and any error.
+//
+// Example:
+//
+//	  func DoSomething() {
+//		n, stdout, stderr, err := capture.OutputValue(func () (int, error) {
+//		   return fmt.Println("some output")
+//		})
+//
+//		fmt.Printf("n: %v", n) // 12
+//		fmt.Printf("stdout: %v", stdout)
+//		fmt.Printf("stderr: %v", stderr)
+//		fmt.Printf("error: %v", err)
+//	  }
+func OutputValue[T any](fn func() (T, error)) (T, []string, []string, error) {
+	var v T
+	stdout, stderr, err := Output(func() error {
+		var err error
+		v, err = fn()
+		return err
+	})
+	return v, stdout, stderr, err
+}
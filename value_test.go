@@ -0,0 +1,47 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestOutputValue(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+
+	// ACT
+	v, stdout, stderr, err := OutputValue(func() (int, error) {
+		fmt.Println("to stdout")
+		return 42, fnerr
+	})
+
+	// ASSERT
+	t.Run("returns value", func(t *testing.T) {
+		wanted := 42
+		got := v
+		if wanted != got {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("output captured", func(t *testing.T) {
+		wanted := []string{"to stdout"}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+		if stderr != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", stderr)
+		}
+	})
+}
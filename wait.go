@@ -0,0 +1,35 @@
+package capture
+
+import "time"
+
+// OutputWait captures the stdout and stderr output produced during
+// execution of a supplied function, as for Output, continuing to
+// capture output for a grace period after the function has returned.
+//
+// This allows output to be captured from goroutines started by the
+// function that are still running when it returns, such as
+// fire-and-forget logging goroutines.
+//
+// NOTE: this is best-effort.  OutputWait always waits for the full
+// grace period (there is no way to know whether any goroutine has
+// further output to write); output written after the grace period has
+// elapsed is not captured.  Such output is either written to the
+// restored os.Stdout or os.Stderr or, if the goroutine is holding the
+// capturing file, dropped (the write fails with os.ErrClosed).
+//
+// Example:
+//
+//	  func TestBackgroundLogging(t *testing.T) {
+//		stdout, _, err := capture.OutputWait(100 * time.Millisecond, func () error {
+//		   go logInBackground("started")
+//		   return nil
+//		})
+//		...
+//	  }
+func OutputWait(grace time.Duration, fn func() error) ([]string, []string, error) {
+	return Output(func() error {
+		err := fn()
+		time.Sleep(grace)
+		return err
+	})
+}
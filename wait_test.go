@@ -0,0 +1,84 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOutputWait(t *testing.T) {
+	t.Run("captures output written within the grace period", func(t *testing.T) {
+		// ARRANGE
+		fnerr := errors.New("function error")
+
+		// ACT
+		stdout, _, err := OutputWait(200*time.Millisecond, func() error {
+			w := os.Stdout
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				fmt.Fprintln(w, "from goroutine")
+			}()
+			fmt.Println("from function")
+			return fnerr
+		})
+
+		// ASSERT
+		t.Run("returns error", func(t *testing.T) {
+			wanted := fnerr
+			got := err
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+			}
+		})
+
+		t.Run("stdout", func(t *testing.T) {
+			wanted := []string{"from function", "from goroutine"}
+			got := stdout
+			if !reflect.DeepEqual(wanted, got) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+	})
+
+	t.Run("drops output written after the grace period", func(t *testing.T) {
+		// ARRANGE
+		werr := make(chan error, 1)
+
+		// ACT
+		stdout, _, err := OutputWait(10*time.Millisecond, func() error {
+			w := os.Stdout
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				_, err := fmt.Fprintln(w, "too late")
+				werr <- err
+			}()
+			return nil
+		})
+
+		// ASSERT
+		t.Run("returns no error", func(t *testing.T) {
+			got := err
+			if got != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", got)
+			}
+		})
+
+		t.Run("stdout", func(t *testing.T) {
+			got := stdout
+			if got != nil {
+				t.Errorf("\nwanted: nil\ngot   : %v", got)
+			}
+		})
+
+		t.Run("late write fails", func(t *testing.T) {
+			wanted := os.ErrClosed
+			got := <-werr
+			if !errors.Is(got, wanted) {
+				t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+			}
+		})
+	})
+}
@@ -0,0 +1,33 @@
+package capture
+
+// OutputWarm captures the stdout and stderr output produced during
+// execution of a supplied warm-up function followed by a supplied
+// function, as for Output.  Both functions run within the same capture,
+// so the returned output is the output of both, in the order produced.
+//
+// The warm-up function runs before the function and is intended for
+// code with side effects that produce output on first use (e.g. lazy
+// initialization), making explicit that such output is expected and
+// is part of the captured output.  If the warm-up function is nil, only
+// the function is called.
+//
+// Error handling is identical to Output.
+//
+// Example:
+//
+//	  func TestFirstUse(t *testing.T) {
+//		stdout, _, err := capture.OutputWarm(
+//		   func() { _ = registry.Default() }, // logs "registry initialised"
+//		   func () error {
+//		      return registry.Default().Register("item")
+//		   })
+//		...
+//	  }
+func OutputWarm(warmup func(), fn func() error) ([]string, []string, error) {
+	return Output(func() error {
+		if warmup != nil {
+			warmup()
+		}
+		return fn()
+	})
+}
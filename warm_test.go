@@ -0,0 +1,59 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestOutputWarm(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	var once sync.Once
+	lazy := func() {
+		once.Do(func() { os.Stderr.WriteString("initialised\n") })
+	}
+
+	// ACT
+	stdout, stderr, err := OutputWarm(lazy, func() error {
+		lazy()
+		fmt.Println("used")
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("output captured", func(t *testing.T) {
+		wanted := [][]string{{"used"}, {"initialised"}}
+		got := [][]string{stdout, stderr}
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("when warm-up is nil", func(t *testing.T) {
+		// ACT
+		stdout, _, err := OutputWarm(nil, func() error {
+			fmt.Println("used")
+			return nil
+		})
+
+		// ASSERT
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if wanted, got := []string{"used"}, stdout; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package capture
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// diagFn returns the writer to which diagnostics are written: the
+// original os.Stderr, before any capture.
+var diagFn = func() io.Writer { return original(&os.Stderr) }
+
+// watchdog is an io.Writer that writes a diagnostic if any write to an
+// underlying writer does not complete within a specified duration.
+type watchdog struct {
+	w      io.Writer
+	d      time.Duration
+	stream Stream
+}
+
+// Write implements io.Writer.
+func (wd *watchdog) Write(p []byte) (int, error) {
+	t := time.AfterFunc(wd.d, func() {
+		fmt.Fprintf(diagFn(), "capture: %s blocked for more than %v; is the captured output being consumed?\n", wd.stream, wd.d)
+	})
+	defer t.Stop()
+
+	return wd.w.Write(p)
+}
@@ -0,0 +1,54 @@
+package capture
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithBlockWarning(t *testing.T) {
+	// ARRANGE
+	diag := &bytes.Buffer{}
+	og := diagFn
+	defer func() { diagFn = og }()
+	sw := &syncWriter{w: diag}
+	diagFn = func() io.Writer { return sw }
+
+	result := func() string {
+		sw.mu.Lock()
+		defer sw.mu.Unlock()
+		return diag.String()
+	}
+
+	t.Run("when capture is not blocked", func(t *testing.T) {
+		// ACT
+		_ = OutputStream(func(Stream, string) {}, func() error {
+			fmt.Println("output")
+			return nil
+		}, WithBlockWarning(time.Second))
+
+		// ASSERT
+		got := result()
+		if got != "" {
+			t.Errorf("\nwanted: \"\"\ngot   : %q", got)
+		}
+	})
+
+	t.Run("when capture is blocked", func(t *testing.T) {
+		// ACT
+		_ = OutputStream(func(Stream, string) { time.Sleep(100 * time.Millisecond) }, func() error {
+			fmt.Println("output")
+			return nil
+		}, WithBlockWarning(10*time.Millisecond))
+
+		// ASSERT
+		wanted := "capture: stdout blocked for more than 10ms; is the captured output being consumed?\n"
+		got := result()
+		if !strings.Contains(got, wanted) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package capture
+
+import (
+	"bytes"
+	"io"
+)
+
+// Writer captures the output written to an arbitrary io.Writer variable
+// during execution of a supplied function.
+//
+// This generalises File to any package that exposes a settable
+// io.Writer sink (e.g. an `Output io.Writer` field or variable).  The
+// variable is replaced with a writer that captures everything written
+// through it for the duration of the call and is restored when the
+// function returns.  Writes to the capturing writer are serialized, so
+// the variable may be written to concurrently.
+//
+// As for File, only writes made through the variable are captured.  In
+// particular, a writer constructed from the value of the variable
+// before calling Writer (e.g. an io.MultiWriter) continues to write to
+// the original writer; to capture such output, the constructed writer
+// must be obtained from the variable during the call.
+//
+// No capture errors can occur; any error returned by the supplied
+// function is returned together with the captured output.
+//
+// Example:
+//
+//	  var Out io.Writer = os.Stdout // some package writer variable
+//
+//	  func DoSomething() {
+//		output, err := capture.Writer(&Out, func () error {
+//		   fmt.Fprintln(io.MultiWriter(Out, logFile), "some output")
+//		   return nil
+//		})
+//
+//		fmt.Printf("output: %v", output) // [some output]
+//		fmt.Printf("error: %v", err)
+//	  }
+func Writer(target *io.Writer, fn func() error) ([]string, error) {
+	mu.lock()
+	defer mu.unlock()
+
+	h := startHook()
+
+	buf := &bytes.Buffer{}
+	sw := &syncWriter{w: h.tee(StdoutStream, buf)}
+
+	og := *target
+	*target = sw
+	defer func() { *target = og }()
+
+	err := fn()
+	*target = og
+
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	h.stop(err)
+	return lines(buf.String()), err
+}
@@ -0,0 +1,72 @@
+package capture
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	// ARRANGE
+	og := &bytes.Buffer{}
+	other := &bytes.Buffer{}
+	var target io.Writer = og
+	fnerr := errors.New("function error")
+
+	// ACT
+	result, err := Writer(&target, func() error {
+		fmt.Fprintln(target, "line 1")
+		fmt.Fprintln(io.MultiWriter(target, other), "line 2")
+
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		go func() { defer wg.Done(); fmt.Fprintln(target, "line 3") }()
+		wg.Wait()
+
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("captured", func(t *testing.T) {
+		wanted := []string{"line 1", "line 2", "line 3"}
+		got := result
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("restores target", func(t *testing.T) {
+		if target != io.Writer(og) {
+			t.Errorf("\nwanted: %p\ngot   : %p", og, target)
+		}
+	})
+
+	t.Run("original writer not written", func(t *testing.T) {
+		if og.Len() != 0 {
+			t.Errorf("\nwanted: <empty>\ngot   : %q", og.String())
+		}
+	})
+
+	t.Run("when no output is written", func(t *testing.T) {
+		// ACT
+		result, _ := Writer(&target, func() error { return nil })
+
+		// ASSERT
+		got := result
+		if got != nil {
+			t.Errorf("\nwanted: nil\ngot   : %v", got)
+		}
+	})
+}
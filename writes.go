@@ -0,0 +1,97 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+)
+
+// OutputWrites captures the stdout and stderr output produced during
+// execution of a supplied function, preserving the boundaries of the
+// individual writes to each file.  Each element of the returned slices
+// holds the bytes written by a single Write call (or by any function,
+// such as fmt.Print or os.File.WriteString, that results in a single
+// Write call).  A Write of no bytes results in an empty element.
+//
+// Other capture functions read output from a pipe, which coalesces
+// writes: output written by consecutive Write calls may be read in a
+// single chunk and a single write may be read in several chunks, so
+// those functions cannot (and do not attempt to) report how output was
+// written.  OutputWrites instead redirects os.Stdout and os.Stderr to
+// sockets that preserve message boundaries.
+//
+// This requires SOCK_SEQPACKET sockets and is currently supported only
+// on linux; on other platforms the function is not called and
+// ErrStdoutCapture is returned, wrapping errors.ErrUnsupported.  A
+// single write is limited to the size of the socket send buffer (a
+// larger write fails, returning an error to the writer).
+//
+// If the sockets cannot be created, the function is not called and
+// ErrStdoutCapture or ErrStderrCapture is returned, wrapping the
+// error.  Otherwise, error handling is identical to Output.
+//
+// Example:
+//
+//	  func TestDoSomething(t *testing.T) {
+//		stdout, _, _ := capture.OutputWrites(func () error {
+//		   fmt.Print("some")
+//		   fmt.Print(" output")
+//		   return nil
+//		})
+//
+//		// stdout: [][]byte{[]byte("some"), []byte(" output")}
+//	  }
+func OutputWrites(fn func() error) ([][]byte, [][]byte, error) {
+	mu.lock()
+	defer mu.unlock()
+
+	h := startHook()
+
+	stopout, err := captureWrites(&os.Stdout)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrStdoutCapture, err)
+		h.stop(err)
+		return nil, nil, err
+	}
+
+	stoperr, err := captureWrites(&os.Stderr)
+	if err != nil {
+		_, _ = stopout()
+		err = fmt.Errorf("%w: %w", ErrStderrCapture, err)
+		h.stop(err)
+		return nil, nil, err
+	}
+
+	var (
+		o       outcome
+		stdout  [][]byte
+		stderr  [][]byte
+		stopped bool
+	)
+	stop := func() {
+		if stopped {
+			return
+		}
+		stopped = true
+
+		if stderr, err = stoperr(); err != nil {
+			stderr, o.stderr = nil, fmt.Errorf("%w: %w", ErrStderrCapture, err)
+		}
+		if stdout, err = stopout(); err != nil {
+			stdout, o.stdout = nil, fmt.Errorf("%w: %w", ErrStdoutCapture, err)
+		}
+	}
+	defer stop() // ensures the files are restored if the function panics
+
+	o.err = fn()
+	stop()
+
+	for _, p := range stdout {
+		h.record(StdoutStream, p)
+	}
+	for _, p := range stderr {
+		h.record(StderrStream, p)
+	}
+	h.stop(o.join())
+
+	return stdout, stderr, o.join()
+}
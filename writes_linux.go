@@ -0,0 +1,84 @@
+package capture
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// captureWrites redirects a file to one of a pair of connected
+// SOCK_SEQPACKET sockets, on which each write is received as a
+// separate message.  The returned function restores the file and
+// returns the messages received, one for each write.
+//
+// A zero-length message cannot be distinguished from the end of the
+// stream when the sending socket is closed, so the end of the capture
+// is instead marked by a message holding a random value, unique to the
+// capture.
+func captureWrites(t **os.File) (func() ([][]byte, error), error) {
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_SEQPACKET|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPipeCreate, err)
+	}
+	rfd := fds[0]
+	w := os.NewFile(uintptr(fds[1]), "capture")
+
+	// no message can be larger than the send buffer of the socket
+	size, err := syscall.GetsockoptInt(fds[1], syscall.SOL_SOCKET, syscall.SO_SNDBUF)
+	if err != nil {
+		_ = syscall.Close(rfd)
+		_ = w.Close()
+		return nil, err
+	}
+
+	eof := make([]byte, 16)
+	if _, err := rand.Read(eof); err != nil {
+		_ = syscall.Close(rfd)
+		_ = w.Close()
+		return nil, err
+	}
+
+	type result struct {
+		writes [][]byte
+		err    error
+	}
+	e := make(chan result)
+	go func() {
+		defer syscall.Close(rfd)
+
+		var (
+			r   result
+			buf = make([]byte, size)
+		)
+		for {
+			n, err := syscall.Read(rfd, buf)
+			if err == syscall.EINTR {
+				continue
+			}
+			if err != nil {
+				r.err = err
+				break
+			}
+			if bytes.Equal(buf[:n], eof) {
+				break
+			}
+			r.writes = append(r.writes, bytes.Clone(buf[:n]))
+		}
+		e <- r
+	}()
+
+	rd := install(t, w)
+
+	return func() ([][]byte, error) {
+		rd.restore()
+		defer w.Close()
+
+		// if the write of the eof marker fails, the reader has already
+		// stopped (with an error)
+		_, _ = w.Write(eof)
+		r := <-e
+		return r.writes, r.err
+	}, nil
+}
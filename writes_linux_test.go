@@ -0,0 +1,105 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestOutputWrites(t *testing.T) {
+	// ARRANGE
+	fnerr := errors.New("function error")
+	og := os.Stdout
+
+	// ACT
+	stdout, stderr, err := OutputWrites(func() error {
+		fmt.Print("one")
+		fmt.Print(" two")
+		fmt.Println()
+		os.Stderr.Write([]byte("three four\n"))
+		os.Stderr.Write(nil)
+		return fnerr
+	})
+
+	// ASSERT
+	t.Run("returns error", func(t *testing.T) {
+		wanted := fnerr
+		got := err
+		if !errors.Is(got, wanted) {
+			t.Errorf("\nwanted: %#v\ngot   : %#v", wanted, got)
+		}
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		wanted := [][]byte{[]byte("one"), []byte(" two"), []byte("\n")}
+		got := stdout
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("stderr", func(t *testing.T) {
+		wanted := [][]byte{[]byte("three four\n"), {}}
+		got := stderr
+		if !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+
+	t.Run("restores stdout", func(t *testing.T) {
+		wanted := og
+		got := os.Stdout
+		if wanted != got {
+			t.Errorf("\nwanted: %v\ngot   : %v", wanted, got)
+		}
+	})
+
+	t.Run("no output", func(t *testing.T) {
+		stdout, stderr, err := OutputWrites(func() error { return nil })
+		if stdout != nil || stderr != nil || err != nil {
+			t.Errorf("\nwanted: nil, nil, nil\ngot   : %q, %q, %v", stdout, stderr, err)
+		}
+	})
+
+	t.Run("large write", func(t *testing.T) {
+		b := make([]byte, 64<<10)
+		stdout, _, err := OutputWrites(func() error {
+			_, err := os.Stdout.Write(b)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		wanted := 1
+		got := len(stdout)
+		if wanted != got {
+			t.Errorf("\nwanted: %d writes\ngot   : %d", wanted, got)
+		}
+	})
+
+	t.Run("nested within Output", func(t *testing.T) {
+		var writes [][]byte
+		lines, _, err := Output(func() error {
+			var err error
+			writes, _, err = OutputWrites(func() error {
+				fmt.Print("inner")
+				return nil
+			})
+			fmt.Println("outer")
+			return err
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if wanted, got := [][]byte{[]byte("inner")}, writes; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+		if wanted, got := []string{"outer"}, lines; !reflect.DeepEqual(wanted, got) {
+			t.Errorf("\nwanted: %q\ngot   : %q", wanted, got)
+		}
+	})
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package capture
+
+import (
+	"errors"
+	"os"
+)
+
+// captureWrites is not supported on this platform.
+func captureWrites(**os.File) (func() ([][]byte, error), error) {
+	return nil, errors.ErrUnsupported
+}